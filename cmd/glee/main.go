@@ -1,15 +1,20 @@
 package main
 
 import (
+	"os"
+
 	"github.com/namsral/flag"
 	log "github.com/sirupsen/logrus"
 	"github.com/tonyOreglia/glee/pkg/commandline"
+	"github.com/tonyOreglia/glee/pkg/uci"
 	"github.com/tonyOreglia/glee/pkg/websocket"
 )
 
 func main() {
 	var serve bool
+	var uciMode bool
 	flag.BoolVar(&serve, "serve", false, "run as a webhook server (defaults to false which runs an interactive command line mode)")
+	flag.BoolVar(&uciMode, "uci", false, "speak UCI over stdin/stdout for use with chess GUIs such as cutechess-cli or Arena")
 	flag.Parse()
 
 	if serve {
@@ -18,6 +23,12 @@ func main() {
 		log.Info("starting websocket server")
 		server.Start()
 	}
+	if uciMode {
+		// stdout carries the protocol so only warnings go to stderr
+		log.SetLevel(log.WarnLevel)
+		uci.Run(os.Stdin, os.Stdout)
+		return
+	}
 	commandline.CLI()
 
 }
@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/tonyOreglia/glee/pkg/engine"
 	"github.com/tonyOreglia/glee/pkg/evaluate"
 	"github.com/tonyOreglia/glee/pkg/generate"
-	"github.com/tonyOreglia/glee/pkg/moves"
 	"github.com/tonyOreglia/glee/pkg/position"
 )
 
@@ -16,7 +16,6 @@ func CLI() {
 	command := make([]byte, 0, 100)
 	pos := position.StartingPosition()
 	mvs := generate.GenerateMoves(pos)
-	var move *moves.Move
 	for true {
 		fmt.Print("glee: ")
 		_, err := fmt.Scan(&command)
@@ -41,11 +40,16 @@ func CLI() {
 			undo(pos)
 			mvs = generate.GenerateMoves(pos)
 		case "search":
-			pos, move = search(pos, mvs)
-			move.Print()
+			result := engine.Search(pos, engine.SearchLimits{Depth: searchDepth}, engine.DefaultSearchOptions(), nil, nil)
+			result.BestMove.Print()
+			printPV(result.PV)
 			mvs = generate.GenerateMoves(pos)
 		case "setboard":
 			setboard(pos)
+		case "perft":
+			runPerft(pos)
+		case "divide":
+			runDivide(pos)
 		case "playw":
 			pos = position.StartingPosition()
 			pos.Print()
@@ -75,9 +79,9 @@ func printHelp() {
 	fmt.Println("search..........engine plays the current position")
 	fmt.Println("playw...........play white vs engine as black")
 	fmt.Println("playb...........play black vs engine as white")
-	// fmt.Println("divide #........outputs the numbers of child moves")
+	fmt.Println("divide #........outputs the numbers of child moves")
 	// fmt.Println("divide2 #.......outputs the total numbers of child moves")
-	// fmt.Println("perft #.........counts nodes at given depth")
+	fmt.Println("perft #.........counts nodes at given depth")
 	// fmt.Println("perft2 #........counts all nodes to given depth")
 	fmt.Println("setboard <FEN>..reads a fen-string")
 	fmt.Println("fen.............outputs FEN of board position")
@@ -92,6 +96,9 @@ func printHelp() {
 func play(p *position.Position, humanSide int) *position.Position {
 	move := make([]byte, 0, 100)
 	for true {
+		if gameOver(p) {
+			return p
+		}
 		if p.GetActiveSide() == humanSide {
 			for true {
 				fmt.Print("human move: ")
@@ -119,13 +126,33 @@ func play(p *position.Position, humanSide int) *position.Position {
 				}
 			}
 		} else {
-			var mv *moves.Move
-			p, mv = search(p, generate.GenerateMoves(p))
-			p.Move(*mv)
+			result := engine.Search(p, engine.SearchLimits{Depth: searchDepth}, engine.DefaultSearchOptions(), nil, nil)
+			p.Move(result.BestMove)
 			p.Print()
 			fmt.Print("glee move: ")
-			mv.Print()
+			result.BestMove.Print()
+		}
+		if p.IsThreefoldRepetition() {
+			fmt.Println("draw by threefold repetition")
+			return p
 		}
 	}
 	return p
 }
+
+// gameOver announces mate or stalemate when the side to move has no legal move
+func gameOver(p *position.Position) bool {
+	if len(generate.GenerateLegalMoves(p).GetMovesList()) > 0 {
+		return false
+	}
+	if !generate.InCheck(p) {
+		fmt.Println("draw by stalemate")
+		return true
+	}
+	if p.IsWhitesTurn() {
+		fmt.Println("checkmate, black wins")
+	} else {
+		fmt.Println("checkmate, white wins")
+	}
+	return true
+}
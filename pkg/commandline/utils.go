@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/tonyOreglia/glee/pkg/engine"
 	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/perft"
 	"github.com/tonyOreglia/glee/pkg/position"
 )
 
@@ -59,27 +63,54 @@ func setboard(p *position.Position) {
 	p, err = position.NewPositionFen(string(fen))
 	if err != nil {
 		badInput(string(fen))
+		return
 	}
 	p.Print()
 }
 
-func search(p *position.Position, mvs *moves.Moves) (*position.Position, *moves.Move) {
-	perft := 0
-	singlePlyPerft := 0
-	params := engine.SearchParams{
-		Depth:          5,
-		Ply:            5,
-		Pos:            &p,
-		Perft:          &perft,
-		SinglePlyPerft: &singlePlyPerft,
-		EngineMove:     &moves.Move{},
+// readDepth reads the depth argument of perft and divide
+func readDepth() (int, bool) {
+	var depth int
+	if _, err := fmt.Scan(&depth); err != nil || depth < 1 {
+		badInput(strconv.Itoa(depth))
+		return 0, false
 	}
-	if p.IsWhitesTurn() {
-		engine.AlphaBetaMax(-10000, 10000, 5, params)
-	} else {
-		engine.AlphaBetaMin(-10000, 10000, 5, params)
+	return depth, true
+}
+
+func runPerft(p *position.Position) {
+	depth, ok := readDepth()
+	if !ok {
+		return
+	}
+	start := time.Now()
+	nodes := perft.Perft(p, depth)
+	fmt.Printf("nodes: %d time: %v\n", nodes, time.Since(start))
+}
+
+func runDivide(p *position.Position) {
+	depth, ok := readDepth()
+	if !ok {
+		return
+	}
+	counts := perft.Divide(p, depth)
+	total := 0
+	for _, count := range counts {
+		fmt.Printf("%s: %d\n", count.Move.String(), count.Nodes)
+		total += count.Nodes
+	}
+	fmt.Printf("moves: %d nodes: %d\n", len(counts), total)
+}
+
+// searchDepth is the depth the engine searches to in the command line interface
+const searchDepth = 5
+
+func printPV(pv []moves.Move) {
+	line := make([]string, len(pv))
+	for i := range pv {
+		line[i] = pv[i].String()
 	}
-	return p, params.EngineMove
+	fmt.Printf("pv: %s\n", strings.Join(line, " "))
 }
 
 func badInput(c string) {
@@ -0,0 +1,46 @@
+package engine
+
+// aspiration windows start aspirationDelta around the score of the previous iteration
+// from aspirationMinDepth on, shallower iterations are too unstable to predict the next one
+const (
+	aspirationMinDepth = 4
+	aspirationDelta    = 25
+)
+
+// aspirationSearch searches the root to depth with a narrow window around guess, the score
+// of the previous iteration, since the narrower the window the more nodes are cut.
+// When the score falls outside the window, report is called with the bound it establishes
+// and the window widens on that side, twice as much each time, until the score fits.
+func (s *searcher) aspirationSearch(depth int, guess int, report func(score int, bound uint8)) int {
+	alpha, beta := -infinity, infinity
+	delta := aspirationDelta
+	// mate scores change a lot from one iteration to the next
+	if depth >= aspirationMinDepth && guess > -mateThreshold && guess < mateThreshold {
+		alpha, beta = guess-delta, guess+delta
+	}
+	for {
+		score := s.negamax(alpha, beta, depth, 0)
+		if s.stopped() {
+			return score
+		}
+		switch {
+		case score <= alpha && alpha > -infinity:
+			report(score, upperBound)
+			alpha -= delta
+			if alpha < -infinity {
+				alpha = -infinity
+			}
+		case score >= beta && beta < infinity:
+			report(score, lowerBound)
+			// the move that failed high is searched first again
+			s.previousBest = s.stack[0].pv[0]
+			beta += delta
+			if beta > infinity {
+				beta = infinity
+			}
+		default:
+			return score
+		}
+		delta *= 2
+	}
+}
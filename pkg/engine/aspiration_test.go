@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestAspirationSearch(t *testing.T) {
+	tests := map[string]struct {
+		offset int
+		bounds []uint8
+	}{
+		"good guess":         {offset: 0, bounds: nil},
+		"guess too low":      {offset: -100, bounds: []uint8{lowerBound, lowerBound, lowerBound}},
+		"guess far too high": {offset: 1000, bounds: []uint8{upperBound, upperBound, upperBound, upperBound, upperBound, upperBound}},
+	}
+	fen := "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"
+	pos, _ := position.NewPositionFen(fen)
+	// selective search depends on move ordering, which re-searches change
+	s := newSearcher(pos, SearchOptions{}, 0, NewTimeManager(0), nil)
+	s.depth = 4
+	exact := s.negamax(-infinity, infinity, 4, 0)
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(fen)
+		s := newSearcher(pos, SearchOptions{}, 0, NewTimeManager(0), nil)
+		s.depth = 4
+		var bounds []uint8
+		score := s.aspirationSearch(4, exact+test.offset, func(score int, bound uint8) {
+			bounds = append(bounds, bound)
+		})
+		assert.Equal(t, exact, score, tName)
+		assert.Equal(t, test.bounds, bounds, tName)
+	}
+}
+
+func TestMateDistancePruning(t *testing.T) {
+	pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	s := newSearcher(pos, DefaultSearchOptions(), 0, NewTimeManager(0), nil)
+	s.depth = 5
+	// a mate in two is already known, nothing three plies down can be as good
+	assert.Equal(t, MateScore-4, s.negamax(MateScore-4, infinity, 2, 3))
+	// nor as bad as being mated at the root
+	assert.Equal(t, -MateScore+3, s.negamax(-infinity, -MateScore+3, 2, 3))
+	assert.Equal(t, 0, s.nodes)
+
+	// the shortest mate is still found
+	pos, _ = position.NewPositionFen("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	result := Search(pos, SearchLimits{Depth: 5}, DefaultSearchOptions(), nil, nil)
+	assert.Equal(t, MateScore-1, result.Score)
+}
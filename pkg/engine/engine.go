@@ -11,57 +11,153 @@ import (
 
 var ht = hashtables.Lookup
 
-type SearchParams struct {
-	Depth           int
-	Ply             int
-	Pos             **position.Position
-	EngineMove      *moves.Move
-	Perft           *int
-	SinglePlyPerft  *int
-	EvaluationScore int
-	Root            bool
+// MateScore is the score of checkmate on the board at the root. Mates found deeper in the tree
+// score one less per ply, so the engine prefers the fastest mate and the longest defence.
+// Scores beyond mateThreshold are mate scores.
+const (
+	MateScore     = 9000
+	mateThreshold = MateScore - 2*MaxDepth
+	// DrawScore is the score of stalemate
+	DrawScore = 0
+	// infinity bounds the search window, it is beyond any score
+	infinity = 10000
+)
+
+// searcher holds the state of a search that is shared by all of its nodes.
+// Scores are from the point of view of the side to move at the node (negamax),
+// so a single routine searches the moves of either side.
+type searcher struct {
+	pos *position.Position
+	// depth is the depth of the current iteration
+	depth    int
+	nodes    int
+	selDepth int
+	// nodeLimit stops the search after that many nodes unless it is zero
+	nodeLimit int
+	timer     *TimeManager
+	// tt caches results between nodes and searches when set
+	tt *TranspositionTable
+	// pawns caches the pawn structure evaluation for the search
+	pawns *evaluate.PawnTable
+	// stack holds the state of the nodes on the current path, indexed by height
+	stack   [MaxDepth + 1]stackEntry
+	history historyTable
+	// previousBest is searched first at the root, it is the best move of the previous iteration
+	previousBest moves.Move
+	// unordered searches moves in generation order, it only serves to measure move ordering
+	unordered bool
+	options   SearchOptions
 }
 
-func MinMax(p SearchParams) int {
-	p.Root = p.Ply == p.Depth
-	if p.Ply == 0 {
-		*p.SinglePlyPerft++
-		return evaluate.EvaluatePosition(*p.Pos)
+// newSearcher prepares the search of pos, which it changes while searching and restores after every move
+func newSearcher(pos *position.Position, options SearchOptions, nodeLimit int, timer *TimeManager, tt *TranspositionTable) *searcher {
+	return &searcher{
+		pos:       pos,
+		options:   options,
+		nodeLimit: nodeLimit,
+		timer:     timer,
+		tt:        tt,
+		pawns:     evaluate.NewPawnTable(),
 	}
-	p.EvaluationScore = 30000
-	if (*p.Pos).IsWhitesTurn() {
-		p.EvaluationScore = -30000
+}
+
+// the clock is read once every timeCheckInterval+1 nodes
+const timeCheckInterval = 2047
+
+// countNode records a visited node and reports whether the search has to stop.
+// The first iteration is never interrupted so that there is always a move to play.
+func (s *searcher) countNode() bool {
+	s.nodes++
+	if s.depth == 1 {
+		return false
 	}
-	mvs := generate.GenerateMoves(*p.Pos)
-	mvList := mvs.GetMovesList()
-	for _, move := range mvList {
-		if !MakeValidMove(move, p.Pos) {
-			continue
-		}
-		evaluateMove(move, p)
+	if s.nodes&timeCheckInterval == 0 && s.timer.TimeUp() {
+		s.timer.Stop()
 	}
-	return p.EvaluationScore
+	return s.timer.Stopped() || s.nodeLimitReached()
 }
 
-// evaluateMove checks if pseudo legal move is valid, and if so
-// increments Ply and calls MinMax to continue the search
-func evaluateMove(move moves.Move, p SearchParams) error {
-	p.Ply = p.Ply - 1
-	temp := MinMax(p)
-	if temp > p.EvaluationScore {
-		p.EvaluationScore = temp
-		if p.Root {
-			*p.EngineMove = move
-		}
+// nodeLimitReached reports whether the search has visited as many nodes as it may
+func (s *searcher) nodeLimitReached() bool {
+	return s.nodeLimit > 0 && s.nodes >= s.nodeLimit
+}
+
+// stopped reports whether the current iteration was interrupted, its result is unusable then
+func (s *searcher) stopped() bool {
+	return s.depth > 1 && (s.timer.Stopped() || s.nodeLimitReached())
+}
+
+// updateSelDepth records the deepest height reached from the root
+func (s *searcher) updateSelDepth(height int) {
+	if height > s.selDepth {
+		s.selDepth = height
 	}
-	if p.Root {
-		// move.Print()
-		// fmt.Println(*p.SinglePlyPerft)
-		*p.Perft += *p.SinglePlyPerft
-		*p.SinglePlyPerft = 0
+}
+
+// probeTT looks up the position in the transposition table and reports whether a stored
+// result is deep enough and bounded tightly enough to return without searching.
+// Otherwise it returns the stored best move, if any, to be searched first.
+func (s *searcher) probeTT(alpha int, beta int, depth int, height int) (int, moves.Move, bool) {
+	if s.tt == nil || height == 0 {
+		return 0, moves.Move{}, false
+	}
+	entry, ok := s.tt.probe(s.pos.Hash())
+	if !ok {
+		return 0, moves.Move{}, false
+	}
+	if int(entry.depth) < depth {
+		return 0, entry.move, false
+	}
+	score := scoreFromTT(int(entry.score), height)
+	switch {
+	case score >= beta && entry.bound != upperBound:
+		return beta, entry.move, true
+	case score <= alpha && entry.bound != lowerBound:
+		return alpha, entry.move, true
+	case entry.bound == exactBound:
+		return score, entry.move, true
+	}
+	return 0, entry.move, false
+}
+
+// storeTT records the result of searching the node at height with depth plies left
+func (s *searcher) storeTT(depth int, height int, score int, bound uint8, move moves.Move) {
+	if s.tt != nil {
+		s.tt.store(s.pos.Hash(), depth, scoreToTT(score, height), bound, move)
 	}
-	*p.Pos = (*p.Pos).UnMakeMove()
-	return nil
+}
+
+// movePicker orders the moves of the node at height, hashMove first
+func (s *searcher) movePicker(height int, hashMove moves.Move) *movePicker {
+	mvs := generate.GenerateMoves(s.pos).GetMovesList()
+	if s.unordered {
+		return newUnorderedMovePicker(mvs)
+	}
+	if height == 0 {
+		hashMove = s.previousBest
+	}
+	return newMovePicker(s.pos, mvs, hashMove, s.stack[height].killers, &s.history[s.pos.GetActiveSide()])
+}
+
+// fiftyMoveLimit is the number of half-moves without a pawn move or capture after which the game is drawn
+const fiftyMoveLimit = 100
+
+// isFiftyMoveDraw reports whether the fifty-move rule ends the game at the current node.
+// A checkmate delivered on the last half-move still counts as a win.
+func (s *searcher) isFiftyMoveDraw() bool {
+	if s.pos.HalfMoveClock() < fiftyMoveLimit {
+		return false
+	}
+	if !generate.InCheck(s.pos) {
+		return true
+	}
+	for _, move := range generate.GenerateMoves(s.pos).GetMovesList() {
+		if MakeValidMove(move, &s.pos) {
+			s.pos = s.pos.UnMakeMove()
+			return true
+		}
+	}
+	return false
 }
 
 func MakeValidMove(move moves.Move, pos **position.Position) bool {
@@ -95,62 +191,106 @@ func castlingMoveIsValid(move moves.Move, pos **position.Position) bool {
 	return true
 }
 
-func AlphaBetaMax(alpha int, beta int, ply int, p SearchParams) int {
-	noMoves := true
-	if ply == 0 {
-		return evaluate.EvaluatePosition(*p.Pos)
-	}
-	p.Root = ply == p.Depth
-	mvs := generate.GenerateMoves(*p.Pos).GetMovesList()
-	for _, move := range mvs {
-		if MakeValidMove(move, p.Pos) {
-			noMoves = false
-			score := AlphaBetaMin(alpha, beta, ply-1, p)
-			*p.Pos = (*p.Pos).UnMakeMove()
-			if score >= beta {
-				return beta
+// negamax searches the node at height with depth plies left within the alpha-beta window.
+// It fails hard, the score returned always lies within the window.
+func (s *searcher) negamax(alpha int, beta int, depth int, height int) int {
+	node := &s.stack[height]
+	node.pvLength = 0
+	if height > 0 && (s.pos.IsRepetition() || s.isFiftyMoveDraw()) {
+		return DrawScore
+	}
+	// mate distance pruning: no line from here scores better than mating at the next ply
+	// or worse than being mated right here, so the window can shrink to those scores
+	if height > 0 {
+		if mated := -MateScore + height; alpha < mated {
+			alpha = mated
+		}
+		if mating := MateScore - height - 1; beta > mating {
+			beta = mating
+		}
+		if alpha >= beta {
+			return alpha
+		}
+	}
+	inCheck := generate.InCheck(s.pos)
+	if inCheck && s.options.CheckExtensions {
+		depth++
+	}
+	if depth <= 0 {
+		return s.quiescence(alpha, beta, height)
+	}
+	if s.countNode() {
+		return 0
+	}
+	s.updateSelDepth(height)
+	// check extensions can go on for as long as there are checks
+	if height >= MaxDepth {
+		return clamp(evaluate.Evaluate(s.pos, s.pawns), alpha, beta)
+	}
+	score, hashMove, ok := s.probeTT(alpha, beta, depth, height)
+	if ok {
+		return score
+	}
+	node.staticEval = evaluate.Evaluate(s.pos, s.pawns)
+	if s.tryNullMove(beta, depth, height, inCheck) {
+		return beta
+	}
+	if s.stopped() {
+		return 0
+	}
+	bound := uint8(upperBound)
+	var bestMove moves.Move
+	legalMoves := 0
+	picker := s.movePicker(height, hashMove)
+	for move, ok := picker.next(); ok; move, ok = picker.next() {
+		quiet := isQuiet(s.pos, move)
+		if !MakeValidMove(move, &s.pos) {
+			continue
+		}
+		legalMoves++
+		node.currentMove = move
+		reduction := s.lateMoveReduction(move, quiet, generate.InCheck(s.pos), inCheck, depth, height, legalMoves)
+		score := s.searchMove(alpha, beta, depth, reduction, height, legalMoves == 1)
+		s.pos = s.pos.UnMakeMove()
+		if s.stopped() {
+			return 0
+		}
+		if score >= beta {
+			if quiet {
+				node.addKiller(move)
+				s.history.addCutoff(s.pos.GetActiveSide(), depth, move)
 			}
-			if score > alpha {
-				alpha = score
-				if p.Root {
-					*p.EngineMove = move
-				}
+			// an aspiration search that fails high reports the move that did
+			if height == 0 {
+				s.updatePV(height, move)
 			}
+			s.storeTT(depth, height, beta, lowerBound, move)
+			return beta
+		}
+		if score > alpha {
+			alpha = score
+			bound = exactBound
+			bestMove = move
+			s.updatePV(height, move)
 		}
 	}
-	if noMoves {
-		// check for mate situation
-		return -2500
+	if legalMoves == 0 {
+		if inCheck {
+			return -MateScore + height
+		}
+		return DrawScore
 	}
+	s.storeTT(depth, height, alpha, bound, bestMove)
 	return alpha
 }
 
-func AlphaBetaMin(alpha int, beta int, ply int, p SearchParams) int {
-	noMoves := true
-	if ply == 0 {
-		return evaluate.EvaluatePosition(*p.Pos)
-	}
-	p.Root = ply == p.Depth
-	mvs := generate.GenerateMoves(*p.Pos).GetMovesList()
-	for _, move := range mvs {
-		if MakeValidMove(move, p.Pos) {
-			noMoves = false
-			score := AlphaBetaMax(alpha, beta, ply-1, p)
-			*p.Pos = (*p.Pos).UnMakeMove()
-			if score <= alpha {
-				return alpha
-			}
-			if score < beta {
-				beta = score
-				if p.Root {
-					*p.EngineMove = move
-				}
-			}
-		}
+// clamp bounds score to the window [alpha, beta]
+func clamp(score int, alpha int, beta int) int {
+	if score < alpha {
+		return alpha
 	}
-	if noMoves {
-		// check for mate situation
-		return 2500
+	if score > beta {
+		return beta
 	}
-	return beta
+	return score
 }
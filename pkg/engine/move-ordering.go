@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// historyLimit caps history scores, all of them are halved once one gets there
+// so that recent cutoffs weigh more than old ones
+const historyLimit = 1 << 20
+
+// historyTable scores quiet moves by the cutoffs they caused anywhere in the tree
+// (history heuristic), indexed by side, origin and destination.
+// It is kept for a whole search, so later iterations profit from earlier ones.
+type historyTable [2][64][64]int
+
+// addCutoff records a quiet move of side that caused a beta cutoff with depth plies left.
+// Deep cutoffs prune larger subtrees and so raise the score more.
+func (h *historyTable) addCutoff(side int, depth int, move moves.Move) {
+	entry := &h[side][move.Origin()][move.Destination()]
+	*entry += depth * depth
+	if *entry < historyLimit {
+		return
+	}
+	for s := range h {
+		for from := range h[s] {
+			for to := range h[s][from] {
+				h[s][from][to] /= 2
+			}
+		}
+	}
+}
+
+// victim returns the piece captured by move, OccupiedSqs when it captures nothing
+func victim(pos *position.Position, move moves.Move) int {
+	captured := pos.PieceAt(1-pos.GetActiveSide(), move.Destination())
+	enPassante := move.Destination() == pos.EnPassante() && pos.PieceAt(pos.GetActiveSide(), move.Origin()) == position.Pawns
+	if captured == position.OccupiedSqs && enPassante {
+		return position.Pawns
+	}
+	return captured
+}
+
+// isQuiet reports whether move neither captures nor promotes
+func isQuiet(pos *position.Position, move moves.Move) bool {
+	return move.PromotionPiece() == 0 && victim(pos, move) == position.OccupiedSqs
+}
+
+// mvvLva scores captures and promotions by most valuable victim, then least valuable attacker
+func mvvLva(pos *position.Position, move moves.Move) int {
+	attacker := pos.PieceAt(pos.GetActiveSide(), move.Origin())
+	return 10*(pieceValues[victim(pos, move)]+pieceValues[move.PromotionPiece()]) - pieceValues[attacker]/10
+}
+
+// stages of the move picker
+const (
+	stageHashMove = iota
+	stageCaptures
+	stageKillers
+	stageQuiets
+	stageLosingCaptures
+	stageDone
+)
+
+type scoredMove struct {
+	move  moves.Move
+	score int
+}
+
+// movePicker hands out the pseudo legal moves of a node one at a time in stages:
+// the hash move, captures and promotions by MVV-LVA, the killer moves, the remaining
+// quiet moves by history score, and finally the captures that lose material by static
+// exchange evaluation. Each stage only picks its best move when asked for it,
+// so a cutoff early on saves ordering the rest of the moves.
+type movePicker struct {
+	stage    int
+	hashMove moves.Move
+	killers  [2]moves.Move
+	captures []scoredMove
+	quiets   []scoredMove
+	// losingCaptures are scored by the material they lose
+	losingCaptures []scoredMove
+	// unordered hands out the moves in generation order, quiets holds all of them then
+	unordered bool
+}
+
+// newMovePicker orders mvs of pos, history holds the scores of the side to move
+func newMovePicker(pos *position.Position, mvs []moves.Move, hashMove moves.Move, killers [2]moves.Move, history *[64][64]int) *movePicker {
+	mp := &movePicker{captures: make([]scoredMove, 0, len(mvs)), quiets: make([]scoredMove, 0, len(mvs))}
+	found := false
+	for _, move := range mvs {
+		if move == hashMove {
+			found = true
+			continue
+		}
+		if isQuiet(pos, move) {
+			mp.quiets = append(mp.quiets, scoredMove{move: move})
+			continue
+		}
+		if exchange := see(pos, move); exchange < 0 {
+			mp.losingCaptures = append(mp.losingCaptures, scoredMove{move: move, score: exchange})
+			continue
+		}
+		mp.captures = append(mp.captures, scoredMove{move: move, score: mvvLva(pos, move)})
+	}
+	if found {
+		mp.hashMove = hashMove
+	} else {
+		mp.stage = stageCaptures
+	}
+	mp.killers = killers
+	for i := range mp.quiets {
+		mp.quiets[i].score = history[mp.quiets[i].move.Origin()][mp.quiets[i].move.Destination()]
+	}
+	return mp
+}
+
+// newUnorderedMovePicker hands out mvs as generated
+func newUnorderedMovePicker(mvs []moves.Move) *movePicker {
+	mp := &movePicker{stage: stageQuiets, unordered: true, quiets: make([]scoredMove, len(mvs))}
+	for i, move := range mvs {
+		mp.quiets[i] = scoredMove{move: move}
+	}
+	return mp
+}
+
+// next returns the next move to search, ok is false once all moves were handed out
+func (mp *movePicker) next() (move moves.Move, ok bool) {
+	for {
+		switch mp.stage {
+		case stageHashMove:
+			mp.stage = stageCaptures
+			return mp.hashMove, true
+		case stageCaptures:
+			if len(mp.captures) > 0 {
+				return pickBest(&mp.captures), true
+			}
+			mp.stage = stageKillers
+		case stageKillers:
+			for i, killer := range mp.killers {
+				mp.killers[i] = moves.Move{}
+				if killer != (moves.Move{}) && removeMove(&mp.quiets, killer) {
+					return killer, true
+				}
+			}
+			mp.stage = stageQuiets
+		case stageQuiets:
+			if len(mp.quiets) == 0 {
+				mp.stage = stageLosingCaptures
+				continue
+			}
+			if mp.unordered {
+				move = mp.quiets[0].move
+				mp.quiets = mp.quiets[1:]
+				return move, true
+			}
+			return pickBest(&mp.quiets), true
+		case stageLosingCaptures:
+			if len(mp.losingCaptures) > 0 {
+				return pickBest(&mp.losingCaptures), true
+			}
+			mp.stage = stageDone
+		default:
+			return moves.Move{}, false
+		}
+	}
+}
+
+// pickBest removes and returns the highest scored move, the first one generated among equals
+func pickBest(mvs *[]scoredMove) moves.Move {
+	list := *mvs
+	best := 0
+	for i := 1; i < len(list); i++ {
+		if list[i].score > list[best].score {
+			best = i
+		}
+	}
+	move := list[best].move
+	copy(list[best:], list[best+1:])
+	*mvs = list[:len(list)-1]
+	return move
+}
+
+// removeMove takes move out of mvs and reports whether it was there
+func removeMove(mvs *[]scoredMove, move moves.Move) bool {
+	list := *mvs
+	for i := range list {
+		if list[i].move == move {
+			copy(list[i:], list[i+1:])
+			*mvs = list[:len(list)-1]
+			return true
+		}
+	}
+	return false
+}
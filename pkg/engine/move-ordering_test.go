@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func pickAll(mp *movePicker) []string {
+	picked := []string{}
+	for move, ok := mp.next(); ok; move, ok = mp.next() {
+		picked = append(picked, move.String())
+	}
+	return picked
+}
+
+func TestMovePicker(t *testing.T) {
+	// the white queen can take a rook or a knight defended by the rook, the e pawn can promote
+	pos, _ := position.NewPositionFen("7k/4P3/8/1n1r4/8/3Q4/8/K7 w - - 0 1")
+	mvs := generate.GenerateMoves(pos).GetMovesList()
+	hashMove := *moves.NewMove([]int{56, 57})
+	killers := [2]moves.Move{*moves.NewMove([]int{56, 48})}
+	var history historyTable
+	history.addCutoff(position.White, 4, *moves.NewMove([]int{43, 15}))
+
+	picked := pickAll(newMovePicker(pos, mvs, hashMove, killers, &history[position.White]))
+	assert.Equal(t, len(mvs), len(picked))
+	assert.Equal(t, []string{"a1b1", "e7e8q", "e7e8r", "d3d5", "e7e8b", "e7e8n"}, picked[:6])
+	// the killer of the node comes first among quiet moves, then the move with a history score
+	assert.Equal(t, []string{"a1a2", "d3h7"}, picked[6:8])
+	// the capture that loses the queen for a knight comes last
+	assert.Equal(t, "d3b5", picked[len(picked)-1])
+
+	// without a hash move or heuristics, captures and promotions still come first
+	picked = pickAll(newMovePicker(pos, mvs, moves.Move{}, [2]moves.Move{}, &[64][64]int{}))
+	assert.Equal(t, []string{"e7e8q", "e7e8r", "d3d5", "e7e8b", "e7e8n"}, picked[:5])
+	assert.Equal(t, "d3b5", picked[len(picked)-1])
+	assert.Equal(t, len(mvs), len(picked))
+
+	// a hash move that is not a legal move here is ignored
+	picked = pickAll(newMovePicker(pos, mvs, *moves.NewMove([]int{0, 8}), [2]moves.Move{}, &[64][64]int{}))
+	assert.Equal(t, len(mvs), len(picked))
+
+	unordered := pickAll(newUnorderedMovePicker(mvs))
+	for i := range mvs {
+		assert.Equal(t, mvs[i].String(), unordered[i])
+	}
+}
+
+func TestKillersAndHistory(t *testing.T) {
+	var node stackEntry
+	first := *moves.NewMove([]int{52, 36})
+	second := *moves.NewMove([]int{51, 35})
+	node.addKiller(first)
+	node.addKiller(first)
+	assert.Equal(t, [2]moves.Move{first, {}}, node.killers)
+	node.addKiller(second)
+	assert.Equal(t, [2]moves.Move{second, first}, node.killers)
+
+	var history historyTable
+	history.addCutoff(position.White, 3, first)
+	history.addCutoff(position.White, 3, first)
+	assert.Equal(t, 18, history[position.White][52][36])
+	assert.Equal(t, 0, history[position.Black][52][36])
+	// history scores are aged once they grow too large
+	history[position.Black][12][28] = historyLimit - 1
+	history.addCutoff(position.Black, 1, *moves.NewMove([]int{12, 28}))
+	assert.Equal(t, historyLimit/2, history[position.Black][12][28])
+	assert.Equal(t, 9, history[position.White][52][36])
+}
+
+var orderingPositions = map[string]string{
+	"initial position": "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+	"kiwipete":         "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"middlegame":       "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+}
+
+// searchNodes counts the nodes of iterative deepening to depth with or without move ordering
+func searchNodes(fen string, depth int, ordered bool) int {
+	pos, _ := position.NewPositionFen(fen)
+	s := newSearcher(pos, DefaultSearchOptions(), 0, NewTimeManager(0), nil)
+	s.unordered = !ordered
+	for d := 1; d <= depth; d++ {
+		s.depth = d
+		s.negamax(-infinity, infinity, d, 0)
+		s.previousBest = s.stack[0].pv[0]
+	}
+	return s.nodes
+}
+
+func TestMoveOrderingReducesNodes(t *testing.T) {
+	for tName, fen := range orderingPositions {
+		ordered := searchNodes(fen, 3, true)
+		unordered := searchNodes(fen, 3, false)
+		assert.True(t, ordered < unordered, "%s: %d nodes ordered, %d unordered", tName, ordered, unordered)
+	}
+}
+
+func benchmarkSearchNodes(b *testing.B, ordered bool) {
+	for i := 0; i < b.N; i++ {
+		nodes := 0
+		for _, fen := range orderingPositions {
+			nodes += searchNodes(fen, 3, ordered)
+		}
+		b.ReportMetric(float64(nodes), "nodes/op")
+	}
+}
+
+func BenchmarkSearchOrdered(b *testing.B) {
+	benchmarkSearchNodes(b, true)
+}
+
+func BenchmarkSearchUnordered(b *testing.B) {
+	benchmarkSearchNodes(b, false)
+}
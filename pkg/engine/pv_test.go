@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestPrincipalVariation(t *testing.T) {
+	tests := map[string]struct {
+		pos   string
+		depth int
+	}{
+		"starting position":      {pos: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", depth: 3},
+		"black to move":          {pos: "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", depth: 4},
+		"many captures":          {pos: "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", depth: 3},
+		"mate ends the pv early": {pos: "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", depth: 3},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		// check extensions would make the line longer than the depth
+		options := DefaultSearchOptions()
+		options.CheckExtensions = false
+		result := Search(pos, SearchLimits{Depth: test.depth}, options, nil, nil)
+		assert.True(t, len(result.PV) > 0, tName)
+		assert.True(t, len(result.PV) <= test.depth, tName)
+		assert.Equal(t, result.BestMove, result.PV[0], tName)
+		// every move of the line must be legal in turn
+		for _, move := range result.PV {
+			found, ok := generate.GenerateMoves(pos).FindMove(move.Origin(), move.Destination(), move.PromotionPiece())
+			assert.True(t, ok, tName)
+			assert.True(t, MakeValidMove(found, &pos), tName)
+		}
+	}
+	pos, _ := position.NewPositionFen("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	result := Search(pos, SearchLimits{Depth: 3}, DefaultSearchOptions(), nil, nil)
+	assert.Equal(t, 1, len(result.PV))
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/tonyOreglia/glee/pkg/evaluate"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// pieceValues ranks pieces for capture ordering, indexed by piece
+var pieceValues = [7]int{position.King: 2000, position.Queen: 900, position.Bishops: 330, position.Knights: 320, position.Rooks: 500, position.Pawns: 100}
+
+// orderCaptures sorts the most valuable victims first, taken by the least valuable attacker (MVV-LVA),
+// so that the cutoffs which keep quiescence search small are found early
+func orderCaptures(pos *position.Position, mvs []moves.Move) []moves.Move {
+	sort.SliceStable(mvs, func(i, j int) bool {
+		return mvvLva(pos, mvs[i]) > mvvLva(pos, mvs[j])
+	})
+	return mvs
+}
+
+// quiescence continues the search below the horizon with captures and promotions only,
+// so that positions are not evaluated in the middle of an exchange.
+// The side to move may stand pat, i.e. decline every capture, so the static evaluation is a lower bound.
+// Captures that lose material by static exchange evaluation are not searched, standing pat is better.
+// height is only used to track the selective depth.
+func (s *searcher) quiescence(alpha int, beta int, height int) int {
+	if s.countNode() {
+		return 0
+	}
+	s.updateSelDepth(height)
+	standPat := evaluate.Evaluate(s.pos, s.pawns)
+	if standPat >= beta {
+		return beta
+	}
+	if standPat > alpha {
+		alpha = standPat
+	}
+	for _, move := range orderCaptures(s.pos, generate.GenerateCaptures(s.pos).GetMovesList()) {
+		if see(s.pos, move) < 0 || !MakeValidMove(move, &s.pos) {
+			continue
+		}
+		score := -s.quiescence(-beta, -alpha, height+1)
+		s.pos = s.pos.UnMakeMove()
+		if s.stopped() {
+			return 0
+		}
+		if score >= beta {
+			return beta
+		}
+		if score > alpha {
+			alpha = score
+		}
+	}
+	return alpha
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestQuiescenceAvoidsHorizonBlunders(t *testing.T) {
+	tests := map[string]struct {
+		pos      string
+		depth    int
+		blunder  string
+		expected string
+	}{
+		"queen does not take a defended pawn":       {pos: "4k3/8/2p5/3p4/8/8/8/3QK3 w - - 0 1", depth: 1, blunder: "d1d5"},
+		"black queen does not take a defended pawn": {pos: "3qk3/8/8/8/3P4/2P5/8/4K3 b - - 0 1", depth: 1, blunder: "d8d4"},
+		// the pawns keep Re2+ from winning the rook a move later
+		"recapture wins the exchange": {pos: "4k3/8/8/3r4/8/8/3RPP2/3RK3 w - - 0 1", depth: 2, expected: "d2d5"},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		result := Search(pos, SearchLimits{Depth: test.depth}, DefaultSearchOptions(), nil, nil)
+		if test.blunder != "" {
+			assert.NotEqual(t, test.blunder, result.BestMove.String(), tName)
+		}
+		if test.expected != "" {
+			assert.Equal(t, test.expected, result.BestMove.String(), tName)
+		}
+		assert.Equal(t, test.pos, pos.GetFenString(), tName)
+	}
+}
+
+func TestOrderCaptures(t *testing.T) {
+	pos, _ := position.NewPositionFen("4k3/8/1q3r2/2PN2P1/8/8/8/4K3 w - - 0 1")
+	mvs := orderCaptures(pos, generate.GenerateCaptures(pos).GetMovesList())
+	order := make([]string, len(mvs))
+	for i := range mvs {
+		order[i] = mvs[i].String()
+	}
+	assert.Equal(t, []string{"c5b6", "d5b6", "g5f6", "d5f6"}, order)
+}
@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"github.com/tonyOreglia/glee/pkg/moves"
+)
+
+// stackEntry is the state of the node at one height of the search stack.
+// Entries outlive the nodes that fill them: killer moves are shared by all nodes at
+// the same height, also across iterations, and a node reads the line of its child.
+type stackEntry struct {
+	// staticEval is the evaluation of the node before searching any move
+	staticEval int
+	// killers are the two most recent quiet moves that caused a cutoff at this height
+	killers [2]moves.Move
+	// currentMove is the move being searched from the node
+	currentMove moves.Move
+	// pv is the best line found from the node, pvLength moves long
+	pv       [MaxDepth + 1]moves.Move
+	pvLength int
+}
+
+// addKiller remembers a quiet cutoff move, replacing the older of the two killers
+func (se *stackEntry) addKiller(move moves.Move) {
+	if se.killers[0] != move {
+		se.killers[1] = se.killers[0]
+		se.killers[0] = move
+	}
+}
+
+// updatePV makes move followed by the line of the child node the best line at height
+func (s *searcher) updatePV(height int, move moves.Move) {
+	node, child := &s.stack[height], &s.stack[height+1]
+	node.pv[0] = move
+	copy(node.pv[1:], child.pv[:child.pvLength])
+	node.pvLength = child.pvLength + 1
+}
+
+// principalVariation returns a copy of the best line from the root
+func (s *searcher) principalVariation() []moves.Move {
+	line := make([]moves.Move, s.stack[0].pvLength)
+	copy(line, s.stack[0].pv[:s.stack[0].pvLength])
+	return line
+}
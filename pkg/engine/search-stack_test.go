@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/evaluate"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestUpdatePV(t *testing.T) {
+	s := newSearcher(position.StartingPosition(), DefaultSearchOptions(), 0, NewTimeManager(0), nil)
+	e2e4 := *moves.NewMove([]int{52, 36})
+	e7e5 := *moves.NewMove([]int{12, 28})
+	g1f3 := *moves.NewMove([]int{62, 45})
+	s.updatePV(2, g1f3)
+	s.updatePV(1, e7e5)
+	s.updatePV(0, e2e4)
+	assert.Equal(t, []moves.Move{e2e4, e7e5, g1f3}, s.principalVariation())
+	// a move without a line below it ends the variation
+	s.stack[1].pvLength = 0
+	s.updatePV(0, e2e4)
+	assert.Equal(t, []moves.Move{e2e4}, s.principalVariation())
+}
+
+func TestSearchStack(t *testing.T) {
+	pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	fen := pos.GetFenString()
+	s := newSearcher(pos, DefaultSearchOptions(), 0, NewTimeManager(0), nil)
+	s.depth = 2
+	s.negamax(-infinity, infinity, 2, 0)
+	assert.Equal(t, evaluate.EvaluatePosition(s.pos), s.stack[0].staticEval)
+	assert.Equal(t, 2, s.stack[0].pvLength)
+	assert.Equal(t, fen, s.pos.GetFenString())
+}
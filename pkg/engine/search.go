@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// MaxDepth bounds iterative deepening when a search is only limited by time
+const MaxDepth = 64
+
+// SearchInfo reports the progress of a search after each completed iteration
+type SearchInfo struct {
+	Depth    int
+	SelDepth int
+	// Score is in centipawns from the point of view of the side to move
+	Score int
+	Nodes int
+	Time  time.Duration
+	PV    []moves.Move
+	// HashFull is the transposition table usage in permille
+	HashFull int
+	// LowerBound and UpperBound are set when the score fell outside the aspiration window,
+	// it is then only a bound and the iteration is searched again with a wider window
+	LowerBound bool
+	UpperBound bool
+}
+
+// NodesPerSecond returns the search speed
+func (si SearchInfo) NodesPerSecond() int {
+	if si.Time <= 0 {
+		return 0
+	}
+	return int(int64(si.Nodes) * int64(time.Second) / int64(si.Time))
+}
+
+// MateIn converts a mate score into the number of moves until mate,
+// negative when the side to move is getting mated. ok is false for other scores.
+func (si SearchInfo) MateIn() (int, bool) {
+	switch {
+	case si.Score > mateThreshold:
+		return (MateScore - si.Score + 1) / 2, true
+	case si.Score < -mateThreshold:
+		return -(MateScore + si.Score) / 2, true
+	}
+	return 0, false
+}
+
+// SearchLimits bound a search, zero values leave it unbounded
+type SearchLimits struct {
+	// Depth is the last iteration searched, MaxDepth when zero
+	Depth int
+	// Nodes stops the search once this many nodes have been visited
+	Nodes int
+	// Time is the budget of a search that is not given a Timer
+	Time time.Duration
+	// Timer lets the caller stop the search, or release a ponder search, from another goroutine.
+	// It takes precedence over Time.
+	Timer *TimeManager
+}
+
+// SearchResult is the outcome of the last completed iteration of a search
+type SearchResult struct {
+	BestMove moves.Move
+	// Score is in centipawns from the point of view of the side to move
+	Score int
+	Depth int
+	Nodes int
+	PV    []moves.Move
+}
+
+// Search runs iterative deepening, searching the position one ply deeper at a time until
+// one of the limits is reached, and returns the result of the last completed iteration.
+// An interrupted iteration is discarded since its result is based on an incomplete tree,
+// except for the first one which always completes so that there is a move to play.
+// The best move of each iteration is searched first in the next one,
+// within an aspiration window around its score.
+// Results are shared between iterations, and with later searches, through tt unless it is nil.
+// options select the selective search techniques to use.
+// When report is not nil it is called with the result of every completed iteration,
+// and with the bound found whenever the score falls outside the aspiration window.
+// pos is left unchanged.
+func Search(pos *position.Position, limits SearchLimits, options SearchOptions, tt *TranspositionTable, report func(SearchInfo)) SearchResult {
+	maxDepth := limits.Depth
+	if maxDepth <= 0 || maxDepth > MaxDepth {
+		maxDepth = MaxDepth
+	}
+	tm := limits.Timer
+	if tm == nil {
+		tm = NewTimeManager(limits.Time)
+	}
+	if tt != nil {
+		tt.NewSearch()
+	}
+	s := newSearcher(pos.Copy(), options, limits.Nodes, tm, tt)
+	result := SearchResult{}
+	info := func(score int, pv []moves.Move) SearchInfo {
+		info := SearchInfo{
+			Depth:    s.depth,
+			SelDepth: s.selDepth,
+			Score:    score,
+			Nodes:    s.nodes,
+			Time:     tm.Elapsed(),
+			PV:       pv,
+		}
+		if tt != nil {
+			info.HashFull = tt.HashFull()
+		}
+		return info
+	}
+	reportBound := func(score int, bound uint8) {
+		if report == nil {
+			return
+		}
+		// a fail high found a better move, after a fail low the previous line is all there is
+		boundInfo := info(score, result.PV)
+		if bound == lowerBound {
+			boundInfo.PV = s.principalVariation()
+			boundInfo.LowerBound = true
+		} else {
+			boundInfo.UpperBound = true
+		}
+		report(boundInfo)
+	}
+	for depth := 1; depth <= maxDepth; depth++ {
+		s.depth = depth
+		s.previousBest = result.BestMove
+		score := s.aspirationSearch(depth, result.Score, reportBound)
+		if s.stopped() {
+			break
+		}
+		result = SearchResult{
+			Score: score,
+			Depth: depth,
+			Nodes: s.nodes,
+			PV:    s.principalVariation(),
+		}
+		if len(result.PV) > 0 {
+			result.BestMove = result.PV[0]
+		}
+		if report != nil {
+			report(info(score, result.PV))
+		}
+		if tm.Stopped() || !tm.CanStartIteration() || s.nodeLimitReached() {
+			break
+		}
+	}
+	// nodes of an interrupted iteration were still searched
+	result.Nodes = s.nodes
+	return result
+}
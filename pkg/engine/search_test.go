@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/evaluate"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestSearchResult(t *testing.T) {
+	pos, _ := position.NewPositionFen("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	var reports []SearchInfo
+	result := Search(pos, SearchLimits{Depth: 3}, DefaultSearchOptions(), nil, func(info SearchInfo) { reports = append(reports, info) })
+	assert.Equal(t, 3, len(reports))
+	assert.Equal(t, "a1a8", result.BestMove.String())
+	assert.Equal(t, MateScore-1, result.Score)
+	assert.Equal(t, 3, result.Depth)
+	assert.Equal(t, reports[2].Nodes, result.Nodes)
+	assert.Equal(t, []moves.Move{result.BestMove}, result.PV)
+}
+
+func TestSearchNodeLimit(t *testing.T) {
+	tests := map[string]struct {
+		limit int
+	}{
+		"stops within the second iteration": {limit: 100},
+		"stops after a few iterations":      {limit: 20000},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+		fen := pos.GetFenString()
+		result := Search(pos, SearchLimits{Nodes: test.limit}, DefaultSearchOptions(), nil, nil)
+		assert.True(t, result.Depth >= 1, tName)
+		assert.True(t, result.Depth < MaxDepth, tName)
+		assert.NotEqual(t, result.BestMove.Origin(), result.BestMove.Destination(), tName)
+		// a limited search searches the iterations it completes exactly like an unlimited one
+		unlimited := Search(pos, SearchLimits{Depth: result.Depth}, DefaultSearchOptions(), nil, nil)
+		assert.Equal(t, unlimited.BestMove, result.BestMove, tName)
+		assert.Equal(t, unlimited.Score, result.Score, tName)
+		assert.Equal(t, fen, pos.GetFenString(), tName)
+	}
+}
+
+func TestEvaluationIsNotAMate(t *testing.T) {
+	assert.True(t, evaluate.MaxScore < mateThreshold)
+	// black is far behind but not mated, so the score must not read as a mate
+	pos, _ := position.NewPositionFen("7k/6pp/8/8/8/QQQQQQQQ/8/RNBQKBNR b - - 0 1")
+	result := Search(pos, SearchLimits{Depth: 1}, DefaultSearchOptions(), nil, nil)
+	assert.Equal(t, -evaluate.MaxScore, result.Score)
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"math/bits"
+
+	"github.com/tonyOreglia/glee/pkg/bitboard"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// attackersOrder is the order in which each side recaptures, least valuable piece first
+var attackersOrder = [6]int{position.Pawns, position.Knights, position.Bishops, position.Rooks, position.Queen, position.King}
+
+// see (static exchange evaluation) returns the material won by move, assuming that both sides
+// keep recapturing on its destination with their least valuable piece for as long as that pays.
+// Pieces uncovered by a capture behind an attacker on the same line (x-rays) join the exchange.
+// Pins and checks are ignored, so the result is an estimate that needs no search.
+func see(pos *position.Position, move moves.Move) int {
+	to := move.Destination()
+	side := pos.GetActiveSide()
+	bitboards := [2][]bitboard.Bitboard{pos.GetWhiteBitboards(), pos.GetBlackBitboards()}
+	occupied := pos.AllOccupiedSqsBb().Value() &^ (uint64(1) << uint(move.Origin()))
+	captured := victim(pos, move)
+	if captured == position.Pawns && pos.PieceAt(1-side, to) == position.OccupiedSqs {
+		// en passante removes the pawn behind the destination
+		occupied &^= uint64(1) << uint(to+[2]int{8, -8}[side])
+	}
+	var gain [32]int
+	gain[0] = pieceValues[captured]
+	onSquare := pos.PieceAt(side, move.Origin())
+	if promotion := move.PromotionPiece(); promotion != 0 {
+		gain[0] += pieceValues[promotion] - pieceValues[position.Pawns]
+		onSquare = promotion
+	}
+	depth := 0
+	for {
+		side = 1 - side
+		attacker, attackerSq := leastValuableAttacker(bitboards[side], to, side, occupied)
+		if attacker == position.OccupiedSqs {
+			break
+		}
+		// the king may only capture on a square the other side no longer defends
+		if attacker == position.King {
+			if defender, _ := leastValuableAttacker(bitboards[1-side], to, 1-side, occupied&^(uint64(1)<<uint(attackerSq))); defender != position.OccupiedSqs {
+				break
+			}
+		}
+		depth++
+		// the score of the side to capture if the exchange ends with this capture
+		gain[depth] = pieceValues[onSquare] - gain[depth-1]
+		// neither side can do better by going on, standing pat is never worse than this
+		if -gain[depth-1] < 0 && gain[depth] < 0 {
+			break
+		}
+		occupied &^= uint64(1) << uint(attackerSq)
+		onSquare = attacker
+		if depth == len(gain)-1 {
+			break
+		}
+	}
+	// each side stops capturing when going on loses more than stopping
+	for ; depth > 0; depth-- {
+		if -gain[depth] < gain[depth-1] {
+			gain[depth-1] = -gain[depth]
+		}
+	}
+	return gain[0]
+}
+
+// leastValuableAttacker finds the cheapest piece of side in pieces that attacks sq, sliders
+// are blocked by occupied. It returns OccupiedSqs when side has no attacker left.
+func leastValuableAttacker(pieces []bitboard.Bitboard, sq int, side int, occupied uint64) (int, int) {
+	attackers := generate.AttackersOf(pieces, sq, side, occupied) & occupied
+	if attackers == 0 {
+		return position.OccupiedSqs, 0
+	}
+	for _, piece := range attackersOrder {
+		if bb := attackers & pieces[piece].Value(); bb != 0 {
+			return piece, bits.TrailingZeros64(bb)
+		}
+	}
+	return position.OccupiedSqs, 0
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestSee(t *testing.T) {
+	tests := map[string]struct {
+		pos      string
+		move     string
+		expected int
+	}{
+		"pawn takes an undefended knight":         {pos: "4k3/8/8/3n4/4P3/8/8/4K3 w - - 0 1", move: "e4d5", expected: 320},
+		"queen takes a pawn defended by a pawn":   {pos: "4k3/8/2p5/3p4/8/8/8/3QK3 w - - 0 1", move: "d1d5", expected: -800},
+		"black queen takes a defended pawn":       {pos: "3qk3/8/8/8/3P4/2P5/8/4K3 b - - 0 1", move: "d8d4", expected: -800},
+		"rook behind rook recaptures":             {pos: "3rk3/8/8/3r4/8/8/3R4/3RK3 w - - 0 1", move: "d2d5", expected: 500},
+		"queen behind bishop recaptures":          {pos: "4k3/8/5p2/4p3/8/2B5/1Q6/4K3 w - - 0 1", move: "c3e5", expected: -130},
+		"king cannot take a defended piece":       {pos: "4k3/3p4/8/8/8/8/3R4/3RK3 w - - 0 1", move: "d2d7", expected: 100},
+		"en passante":                             {pos: "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", move: "e5d6", expected: 100},
+		"promotion capture is recaptured by king": {pos: "3rk3/4P3/8/8/8/8/8/4K3 w - - 0 1", move: "e7d8q", expected: 400},
+		"equal trade of knights":                  {pos: "4k3/8/4p3/3n4/8/4N3/8/4K3 w - - 0 1", move: "e3d5", expected: 0},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		origin, _ := moves.ConvertAlgebriacToIndex(test.move[:2])
+		dest, _ := moves.ConvertAlgebriacToIndex(test.move[2:4])
+		move := *moves.NewMove([]int{origin, dest})
+		if len(test.move) == 5 {
+			move = *moves.NewPromoMove([]int{origin, dest, position.Queen})
+		}
+		assert.Equal(t, test.expected, see(pos, move), tName)
+		assert.Equal(t, test.pos, pos.GetFenString(), tName)
+	}
+}
+
+func BenchmarkSee(b *testing.B) {
+	pos, _ := position.NewPositionFen("3rk3/8/8/3r4/8/8/3R4/3RK3 w - - 0 1")
+	move := *moves.NewMove([]int{51, 27})
+	for i := 0; i < b.N; i++ {
+		see(pos, move)
+	}
+}
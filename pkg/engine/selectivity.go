@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// SearchOptions switch the selective search techniques on and off,
+// so that the contribution of each can be measured
+type SearchOptions struct {
+	// NullMove prunes nodes where passing the turn still fails high
+	NullMove bool
+	// LMR searches quiet moves late in the move order to a reduced depth
+	LMR bool
+	// CheckExtensions search one ply deeper when the side to move is in check
+	CheckExtensions bool
+	// PVS searches all moves but the first with a null window, expecting them to fail low
+	PVS bool
+}
+
+// DefaultSearchOptions enables every technique
+func DefaultSearchOptions() SearchOptions {
+	return SearchOptions{
+		NullMove:        true,
+		LMR:             true,
+		CheckExtensions: true,
+		PVS:             true,
+	}
+}
+
+// the null move search is this many plies shallower than a regular move,
+// deepNullMoveReduction applies from nullMoveDeepDepth on
+const (
+	nullMoveMinDepth      = 3
+	nullMoveReduction     = 2
+	nullMoveDeepDepth     = 7
+	deepNullMoveReduction = 3
+)
+
+// nullMove is the empty move recorded on the search stack while a null move is searched
+var nullMove = moves.Move{}
+
+// tryNullMove reports whether the node at height may be pruned because the side to move
+// is doing so well that even passing the turn fails high
+func (s *searcher) tryNullMove(beta int, depth int, height int, inCheck bool) bool {
+	node := &s.stack[height]
+	if !s.options.NullMove || height == 0 || inCheck || depth < nullMoveMinDepth ||
+		node.staticEval < beta || s.stack[height-1].currentMove == nullMove || !hasPieces(s.pos) {
+		return false
+	}
+	reduction := nullMoveReduction
+	if depth >= nullMoveDeepDepth {
+		reduction = deepNullMoveReduction
+	}
+	node.currentMove = nullMove
+	s.pos.MakeNullMove()
+	score := -s.negamax(-beta, -beta+1, depth-1-reduction, height+1)
+	s.pos = s.pos.UnMakeMove()
+	return score >= beta
+}
+
+// hasPieces reports whether the side to move has anything besides pawns and king.
+// Without pieces zugzwang is common, passing would be better than any legal move
+// and the null move would prune positions that are actually lost.
+func hasPieces(pos *position.Position) bool {
+	bbs := pos.GetActiveSidesBitboards()
+	return bbs[position.Queen].Value()|bbs[position.Rooks].Value()|bbs[position.Bishops].Value()|bbs[position.Knights].Value() != 0
+}
+
+// late move reductions apply to quiet moves from lmrMinDepth and after lmrMinMoves moves,
+// deeper ones after lmrDeepMoves moves are reduced one more ply
+const (
+	lmrMinDepth  = 3
+	lmrMinMoves  = 3
+	lmrDeepDepth = 6
+	lmrDeepMoves = 6
+)
+
+// lateMoveReduction returns how many plies less than usual to search a move.
+// Moves that capture, promote, give check or escape check are never reduced,
+// nor are the killers, since any of them is likely to matter.
+func (s *searcher) lateMoveReduction(move moves.Move, quiet bool, givesCheck bool, inCheck bool, depth int, height int, legalMoves int) int {
+	killers := s.stack[height].killers
+	if !s.options.LMR || !quiet || givesCheck || inCheck || depth < lmrMinDepth || legalMoves <= lmrMinMoves ||
+		move == killers[0] || move == killers[1] {
+		return 0
+	}
+	if depth >= lmrDeepDepth && legalMoves > lmrDeepMoves {
+		return 2
+	}
+	return 1
+}
+
+// searchMove searches the child reached by the move just made. The first move gets the full window.
+// With PVS later moves only get a null window, proving that they are not better than alpha
+// is cheaper than finding their score, and they are searched again when they turn out better.
+// A reduced move that beats alpha is searched again to the full depth.
+func (s *searcher) searchMove(alpha int, beta int, depth int, reduction int, height int, first bool) int {
+	if first {
+		return -s.negamax(-beta, -alpha, depth-1, height+1)
+	}
+	searchBeta := beta
+	if s.options.PVS {
+		searchBeta = alpha + 1
+	}
+	score := -s.negamax(-searchBeta, -alpha, depth-1-reduction, height+1)
+	if score > alpha && reduction > 0 {
+		score = -s.negamax(-searchBeta, -alpha, depth-1, height+1)
+	}
+	if score > alpha && score < beta && searchBeta < beta {
+		score = -s.negamax(-beta, -alpha, depth-1, height+1)
+	}
+	return score
+}
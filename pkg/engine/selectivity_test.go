@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestSearchOptions(t *testing.T) {
+	tests := map[string]SearchOptions{
+		"all":             DefaultSearchOptions(),
+		"none":            {},
+		"null move only":  {NullMove: true},
+		"lmr only":        {LMR: true},
+		"extensions only": {CheckExtensions: true},
+		"pvs only":        {PVS: true},
+	}
+	for tName, options := range tests {
+		pos, _ := position.NewPositionFen("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+		result := Search(pos, SearchLimits{Depth: 4}, options, nil, nil)
+		assert.Equal(t, "a1a8", result.BestMove.String(), tName)
+		assert.Equal(t, MateScore-1, result.Score, tName)
+
+		// the attacked queen has to get away, it is still worth more than the rook
+		pos, _ = position.NewPositionFen("4k3/8/8/3q4/8/8/8/3RK3 b - - 0 1")
+		result = Search(pos, SearchLimits{Depth: 4}, options, nil, nil)
+		assert.True(t, result.Score > 300, "%s: score %d", tName, result.Score)
+	}
+}
+
+func TestSelectivityReducesNodes(t *testing.T) {
+	pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	plain := Search(pos, SearchLimits{Depth: 5}, SearchOptions{}, nil, nil)
+	selective := Search(pos, SearchLimits{Depth: 5}, SearchOptions{NullMove: true, LMR: true, PVS: true}, nil, nil)
+	assert.True(t, selective.Nodes < plain.Nodes, "%d nodes selective, %d plain", selective.Nodes, plain.Nodes)
+}
+
+func TestHasPieces(t *testing.T) {
+	tests := map[string]struct {
+		pos    string
+		pieces bool
+	}{
+		"pawns only":                   {pos: "4k3/pppp4/8/8/8/8/PPPP4/4K3 w - - 0 1", pieces: false},
+		"only the other side has some": {pos: "4k3/pppp4/8/8/8/8/PPPP4/R3K3 b - - 0 1", pieces: false},
+		"a knight":                     {pos: "4k3/pppp4/8/8/8/8/PPPP4/1N2K3 w - - 0 1", pieces: true},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		assert.Equal(t, test.pieces, hasPieces(pos), tName)
+	}
+}
+
+func TestNullMoveSkipsZugzwang(t *testing.T) {
+	// with only pawns and king passing is often the best move, which chess does not allow
+	pos, _ := position.NewPositionFen("4k3/4p3/8/8/8/8/4P3/4K3 w - - 0 1")
+	s := newSearcher(pos, DefaultSearchOptions(), 0, NewTimeManager(0), nil)
+	s.depth = nullMoveMinDepth
+	s.stack[0].currentMove = *moves.NewMove([]int{4, 5})
+	s.stack[1].staticEval = infinity
+	assert.False(t, s.tryNullMove(0, nullMoveMinDepth, 1, false))
+	assert.Equal(t, 0, s.nodes)
+
+	pos, _ = position.NewPositionFen("4k3/4p3/8/8/8/8/4P3/1N2K3 w - - 0 1")
+	s = newSearcher(pos, DefaultSearchOptions(), 0, NewTimeManager(0), nil)
+	s.depth = nullMoveMinDepth
+	s.stack[0].currentMove = *moves.NewMove([]int{4, 5})
+	s.stack[1].staticEval = infinity
+	assert.True(t, s.tryNullMove(0, nullMoveMinDepth, 1, false))
+	assert.True(t, s.nodes > 0)
+}
@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// moves assumed to remain in the game when the GUI does not send movestogo
+const defaultMovesToGo = 30
+
+// time kept in reserve for GUI and transport latency
+const moveOverhead = 20 * time.Millisecond
+
+// minimumBudget is spent on a move even when the clock is nearly empty
+const minimumBudget = 10 * time.Millisecond
+
+// TimeControl holds the clock state sent by the GUI with a UCI go command
+type TimeControl struct {
+	WhiteTime      time.Duration
+	BlackTime      time.Duration
+	WhiteIncrement time.Duration
+	BlackIncrement time.Duration
+	MovesToGo      int
+	MoveTime       time.Duration
+}
+
+// Budget allots the time to spend on the next move for the given side.
+// A zero budget means the search is not limited by time.
+func (tc TimeControl) Budget(activeSide int) time.Duration {
+	if tc.MoveTime > 0 {
+		return maxDuration(tc.MoveTime-moveOverhead, minimumBudget)
+	}
+	remaining, increment := tc.WhiteTime, tc.WhiteIncrement
+	if activeSide == position.Black {
+		remaining, increment = tc.BlackTime, tc.BlackIncrement
+	}
+	if remaining <= 0 {
+		return 0
+	}
+	movesToGo := tc.MovesToGo
+	if movesToGo <= 0 {
+		movesToGo = defaultMovesToGo
+	}
+	budget := remaining/time.Duration(movesToGo) + increment*3/4
+	// never plan to use more than what is left on the clock
+	if budget > remaining-moveOverhead {
+		budget = remaining - moveOverhead
+	}
+	return maxDuration(budget, minimumBudget)
+}
+
+func maxDuration(a time.Duration, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// TimeManager tells a running search when to stop, either because its budget is spent
+// or because it was asked to from another goroutine.
+type TimeManager struct {
+	budget   time.Duration
+	infinite bool
+	// start is in unix nanoseconds, it is reset when a ponder search becomes a real one
+	start     int64
+	stopped   int32
+	pondering int32
+	mu        sync.Mutex
+	released  *sync.Cond
+}
+
+// NewTimeManager starts the clock for a search. A zero budget never runs out.
+func NewTimeManager(budget time.Duration) *TimeManager {
+	tm := &TimeManager{budget: budget, start: time.Now().UnixNano()}
+	tm.released = sync.NewCond(&tm.mu)
+	return tm
+}
+
+// NewInfiniteTimeManager creates a time manager for a search that only ends when stopped
+func NewInfiniteTimeManager() *TimeManager {
+	tm := NewTimeManager(0)
+	tm.infinite = true
+	return tm
+}
+
+// NewPonderTimeManager creates a time manager for a search on the opponent's time.
+// The budget only starts running once PonderHit is called.
+func NewPonderTimeManager(budget time.Duration) *TimeManager {
+	tm := NewTimeManager(budget)
+	tm.pondering = 1
+	return tm
+}
+
+// Elapsed returns the time since the search started, or since the ponder hit
+func (tm *TimeManager) Elapsed() time.Duration {
+	return time.Duration(time.Now().UnixNano() - atomic.LoadInt64(&tm.start))
+}
+
+// TimeUp reports whether the whole budget has been used
+func (tm *TimeManager) TimeUp() bool {
+	return !tm.Pondering() && tm.budget > 0 && tm.Elapsed() >= tm.budget
+}
+
+// CanStartIteration reports whether there is likely enough time left to complete another,
+// deeper iteration. Each iteration typically costs more than all previous ones combined.
+func (tm *TimeManager) CanStartIteration() bool {
+	return tm.Pondering() || tm.budget == 0 || tm.Elapsed() < tm.budget/2
+}
+
+// Stop asks the search to return as soon as possible
+func (tm *TimeManager) Stop() {
+	tm.mu.Lock()
+	atomic.StoreInt32(&tm.stopped, 1)
+	tm.released.Broadcast()
+	tm.mu.Unlock()
+}
+
+// Stopped reports whether the search has been asked to stop
+func (tm *TimeManager) Stopped() bool {
+	return atomic.LoadInt32(&tm.stopped) == 1
+}
+
+// PonderHit turns a ponder search into a normal timed search without restarting it
+func (tm *TimeManager) PonderHit() {
+	tm.mu.Lock()
+	atomic.StoreInt64(&tm.start, time.Now().UnixNano())
+	atomic.StoreInt32(&tm.pondering, 0)
+	tm.released.Broadcast()
+	tm.mu.Unlock()
+}
+
+// Pondering reports whether the search is still running on the opponent's time
+func (tm *TimeManager) Pondering() bool {
+	return atomic.LoadInt32(&tm.pondering) == 1
+}
+
+// WaitForRelease blocks until the search result may be reported.
+// UCI forbids sending bestmove during an infinite or ponder search before stop or ponderhit,
+// even when the search itself has already finished.
+func (tm *TimeManager) WaitForRelease() {
+	tm.mu.Lock()
+	for !tm.Stopped() && (tm.infinite || tm.Pondering()) {
+		tm.released.Wait()
+	}
+	tm.mu.Unlock()
+}
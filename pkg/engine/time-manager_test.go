@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestBudget(t *testing.T) {
+	tests := map[string]struct {
+		tc         TimeControl
+		activeSide int
+		expected   time.Duration
+	}{
+		"no clock means no time limit": {
+			tc:         TimeControl{},
+			activeSide: position.White,
+			expected:   0,
+		},
+		"fixed move time keeps a reserve for overhead": {
+			tc:         TimeControl{MoveTime: time.Second},
+			activeSide: position.White,
+			expected:   time.Second - moveOverhead,
+		},
+		"sudden death spreads the clock over the default number of moves": {
+			tc:         TimeControl{WhiteTime: 60 * time.Second, BlackTime: time.Second},
+			activeSide: position.White,
+			expected:   2 * time.Second,
+		},
+		"black uses black's clock and increment": {
+			tc:         TimeControl{WhiteTime: time.Second, BlackTime: 30 * time.Second, BlackIncrement: time.Second},
+			activeSide: position.Black,
+			expected:   time.Second + 750*time.Millisecond,
+		},
+		"moves to go": {
+			tc:         TimeControl{WhiteTime: 10 * time.Second, MovesToGo: 5},
+			activeSide: position.White,
+			expected:   2 * time.Second,
+		},
+		"never plans to use more than what is left": {
+			tc:         TimeControl{WhiteTime: 100 * time.Millisecond, WhiteIncrement: time.Second},
+			activeSide: position.White,
+			expected:   80 * time.Millisecond,
+		},
+	}
+	for tName, test := range tests {
+		assert.Equal(t, test.expected, test.tc.Budget(test.activeSide), tName)
+	}
+}
+
+func TestIterativeDeepeningRespectsBudget(t *testing.T) {
+	pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	fen := pos.GetFenString()
+	tm := NewTimeManager(200 * time.Millisecond)
+	result := Search(pos, SearchLimits{Timer: tm}, DefaultSearchOptions(), nil, nil)
+	assert.True(t, tm.Elapsed() < time.Second)
+	assert.NotEqual(t, result.BestMove.Origin(), result.BestMove.Destination())
+	// an interrupted search leaves the position as it found it
+	assert.Equal(t, fen, pos.GetFenString())
+}
+
+func TestIterativeDeepeningFindsMate(t *testing.T) {
+	pos, _ := position.NewPositionFen("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	result := Search(pos, SearchLimits{Depth: 3}, DefaultSearchOptions(), nil, nil)
+	assert.Equal(t, "a1a8", result.BestMove.String())
+}
+
+func TestStopInterruptsSearch(t *testing.T) {
+	pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	fen := pos.GetFenString()
+	tm := NewInfiniteTimeManager()
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		tm.Stop()
+	}()
+	result := Search(pos, SearchLimits{Timer: tm}, DefaultSearchOptions(), nil, nil)
+	assert.True(t, tm.Elapsed() < time.Second)
+	assert.NotEqual(t, result.BestMove.Origin(), result.BestMove.Destination())
+	assert.Equal(t, fen, pos.GetFenString())
+}
+
+func TestPonderTimeManager(t *testing.T) {
+	tm := NewPonderTimeManager(10 * time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, tm.TimeUp())
+	assert.True(t, tm.CanStartIteration())
+	tm.PonderHit()
+	assert.False(t, tm.Pondering())
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, tm.TimeUp())
+	// released right away once pondering is over
+	tm.WaitForRelease()
+}
+
+func TestTerminalScores(t *testing.T) {
+	tests := map[string]struct {
+		pos    string
+		depth  int
+		score  int
+		mateIn int
+		move   string
+	}{
+		"stalemate is a draw":   {pos: "7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", depth: 2, score: DrawScore},
+		"white mates in one":    {pos: "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", depth: 3, score: MateScore - 1, mateIn: 1, move: "a1a8"},
+		"black mates in one":    {pos: "r5k1/8/8/8/8/8/5PPP/6K1 b - - 0 1", depth: 3, score: MateScore - 1, mateIn: 1, move: "a8a1"},
+		"white mates in two":    {pos: "k7/8/2K5/8/8/8/8/7R w - - 0 1", depth: 4, score: MateScore - 3, mateIn: 2},
+		"black is mated in one": {pos: "k7/7R/1K6/8/8/8/8/8 b - - 0 1", depth: 3, score: -MateScore + 2, mateIn: -1, move: "a8b8"},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		var last SearchInfo
+		result := Search(pos, SearchLimits{Depth: test.depth}, DefaultSearchOptions(), nil, func(info SearchInfo) { last = info })
+		assert.Equal(t, test.score, last.Score, tName)
+		mateIn, ok := last.MateIn()
+		assert.Equal(t, test.mateIn != 0, ok, tName)
+		assert.Equal(t, test.mateIn, mateIn, tName)
+		if test.move != "" {
+			assert.Equal(t, test.move, result.BestMove.String(), tName)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"unsafe"
+
+	"github.com/tonyOreglia/glee/pkg/moves"
+)
+
+// DefaultHashSize is the transposition table size in megabytes unless configured otherwise
+const DefaultHashSize = 16
+
+// bound types of a stored score, zero marks an empty entry
+const (
+	exactBound = iota + 1
+	// lowerBound scores caused a beta cutoff, the true score is at least as high
+	lowerBound
+	// upperBound scores failed low, the true score is at most as high
+	upperBound
+)
+
+type ttEntry struct {
+	key        uint64
+	move       moves.Move
+	score      int32
+	depth      int16
+	bound      uint8
+	generation uint8
+}
+
+// ttBucket holds an entry that is only replaced by a deeper (or newer) search of a position
+// and an entry that is always replaced, so that deep results survive
+// while recent shallow results are still kept. A replaced depth-preferred entry
+// moves to the always-replace one rather than being lost.
+type ttBucket struct {
+	depthPreferred ttEntry
+	alwaysReplace  ttEntry
+}
+
+// TranspositionTable caches search results by position hash so that positions reached
+// through different move orders are only searched once.
+type TranspositionTable struct {
+	buckets    []ttBucket
+	mask       uint64
+	generation uint8
+}
+
+// NewTranspositionTable allocates a table of at most sizeMB megabytes
+func NewTranspositionTable(sizeMB int) *TranspositionTable {
+	if sizeMB < 1 {
+		sizeMB = 1
+	}
+	// round down to a power of two so that the index is a simple mask of the hash
+	count := uint64(1)
+	for count*2*uint64(unsafe.Sizeof(ttBucket{})) <= uint64(sizeMB)<<20 {
+		count *= 2
+	}
+	return &TranspositionTable{
+		buckets: make([]ttBucket, count),
+		mask:    count - 1,
+	}
+}
+
+// Clear empties the table, e.g. for a new game
+func (tt *TranspositionTable) Clear() {
+	for i := range tt.buckets {
+		tt.buckets[i] = ttBucket{}
+	}
+	tt.generation = 0
+}
+
+// NewSearch ages the current entries so that they are replaced first
+func (tt *TranspositionTable) NewSearch() {
+	tt.generation++
+}
+
+func (tt *TranspositionTable) probe(key uint64) (ttEntry, bool) {
+	bucket := &tt.buckets[key&tt.mask]
+	if bucket.depthPreferred.bound != 0 && bucket.depthPreferred.key == key {
+		return bucket.depthPreferred, true
+	}
+	if bucket.alwaysReplace.bound != 0 && bucket.alwaysReplace.key == key {
+		return bucket.alwaysReplace, true
+	}
+	return ttEntry{}, false
+}
+
+func (tt *TranspositionTable) store(key uint64, depth int, score int, bound uint8, move moves.Move) {
+	entry := ttEntry{
+		key:        key,
+		move:       move,
+		score:      int32(score),
+		depth:      int16(depth),
+		bound:      bound,
+		generation: tt.generation,
+	}
+	bucket := &tt.buckets[key&tt.mask]
+	slot := &bucket.depthPreferred
+	if slot.bound == 0 || slot.key == key || slot.generation != tt.generation || depth >= int(slot.depth) {
+		if slot.bound != 0 && slot.key != key {
+			bucket.alwaysReplace = *slot
+		}
+		*slot = entry
+		return
+	}
+	bucket.alwaysReplace = entry
+}
+
+// HashFull estimates how full the table is in permille from a sample of its buckets
+func (tt *TranspositionTable) HashFull() int {
+	sample := 500
+	if len(tt.buckets) < sample {
+		sample = len(tt.buckets)
+	}
+	used := 0
+	for i := 0; i < sample; i++ {
+		if tt.buckets[i].depthPreferred.bound != 0 && tt.buckets[i].depthPreferred.generation == tt.generation {
+			used++
+		}
+		if tt.buckets[i].alwaysReplace.bound != 0 && tt.buckets[i].alwaysReplace.generation == tt.generation {
+			used++
+		}
+	}
+	return used * 1000 / (2 * sample)
+}
+
+// scoreToTT converts mate scores from distance to the root into distance to the stored position,
+// which stays correct wherever in a later search the position is found again
+func scoreToTT(score int, height int) int {
+	if score > mateThreshold {
+		return score + height
+	}
+	if score < -mateThreshold {
+		return score - height
+	}
+	return score
+}
+
+// scoreFromTT converts a stored mate score back into distance from the current root
+func scoreFromTT(score int, height int) int {
+	if score > mateThreshold {
+		return score - height
+	}
+	if score < -mateThreshold {
+		return score + height
+	}
+	return score
+}
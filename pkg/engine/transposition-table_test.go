@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestTranspositionTableSize(t *testing.T) {
+	tt := NewTranspositionTable(1)
+	assert.Equal(t, 0, len(tt.buckets)&(len(tt.buckets)-1))
+	assert.True(t, len(tt.buckets)*2 > (1<<20)/int(unsafe.Sizeof(ttBucket{})))
+	assert.True(t, len(tt.buckets) <= (1<<20)/int(unsafe.Sizeof(ttBucket{})))
+}
+
+func TestTranspositionTableReplacement(t *testing.T) {
+	tt := NewTranspositionTable(1)
+	move := *moves.NewMove([]int{52, 36})
+	key := uint64(12345)
+	other := key + uint64(len(tt.buckets))
+
+	tt.store(key, 5, 100, exactBound, move)
+	entry, ok := tt.probe(key)
+	assert.True(t, ok)
+	assert.Equal(t, ttEntry{key: key, move: move, score: 100, depth: 5, bound: exactBound}, entry)
+
+	// a shallower result for another position in the bucket keeps the deep entry
+	tt.store(other, 2, -50, lowerBound, move)
+	_, ok = tt.probe(key)
+	assert.True(t, ok)
+	entry, ok = tt.probe(other)
+	assert.True(t, ok)
+	assert.Equal(t, int32(-50), entry.score)
+
+	// a deeper one takes the depth-preferred entry and moves the old deep entry to the other one
+	tt.store(other, 6, -60, upperBound, move)
+	entry, ok = tt.probe(other)
+	assert.True(t, ok)
+	assert.Equal(t, int16(6), entry.depth)
+	entry, ok = tt.probe(key)
+	assert.True(t, ok)
+	assert.Equal(t, ttEntry{key: key, move: move, score: 100, depth: 5, bound: exactBound}, entry)
+
+	// the next result for the bucket pushes it out
+	third := other + uint64(len(tt.buckets))
+	tt.store(third, 1, 0, exactBound, move)
+	_, ok = tt.probe(key)
+	assert.False(t, ok)
+
+	// entries of earlier searches are replaced regardless of depth
+	tt.NewSearch()
+	tt.store(key, 1, 10, exactBound, move)
+	entry, ok = tt.probe(key)
+	assert.True(t, ok)
+	assert.Equal(t, int16(1), entry.depth)
+
+	for i := uint64(0); i < 500; i++ {
+		tt.store(i, 1, 0, exactBound, move)
+	}
+	assert.Equal(t, 500, tt.HashFull())
+
+	tt.Clear()
+	_, ok = tt.probe(key)
+	assert.False(t, ok)
+	assert.Equal(t, 0, tt.HashFull())
+}
+
+func TestMateScoreAdjustment(t *testing.T) {
+	tests := map[string]struct {
+		score  int
+		height int
+		stored int
+	}{
+		"regular score":    {score: 120, height: 4, stored: 120},
+		"mating":           {score: MateScore - 7, height: 4, stored: MateScore - 3},
+		"getting mated":    {score: -MateScore + 7, height: 4, stored: -MateScore + 3},
+		"mate at the node": {score: MateScore - 4, height: 4, stored: MateScore},
+	}
+	for tName, test := range tests {
+		assert.Equal(t, test.stored, scoreToTT(test.score, test.height), tName)
+		assert.Equal(t, test.score, scoreFromTT(test.stored, test.height), tName)
+	}
+}
+
+func TestSearchWithTranspositionTable(t *testing.T) {
+	fens := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+	}
+	for _, fen := range fens {
+		pos, _ := position.NewPositionFen(fen)
+		var withoutTT, firstSearch, secondSearch SearchInfo
+		Search(pos, SearchLimits{Depth: 3}, DefaultSearchOptions(), nil, func(info SearchInfo) { withoutTT = info })
+		tt := NewTranspositionTable(1)
+		Search(pos, SearchLimits{Depth: 3}, DefaultSearchOptions(), tt, func(info SearchInfo) { firstSearch = info })
+		assert.Equal(t, withoutTT.Score, firstSearch.Score, fen)
+		entry, ok := tt.probe(pos.Hash())
+		assert.True(t, ok, fen)
+		assert.Equal(t, int16(3), entry.depth, fen)
+		// searching the same position again is answered from the table
+		Search(pos, SearchLimits{Depth: 3}, DefaultSearchOptions(), tt, func(info SearchInfo) { secondSearch = info })
+		assert.Equal(t, firstSearch.Score, secondSearch.Score, fen)
+		assert.True(t, secondSearch.Nodes < firstSearch.Nodes, fen)
+		assert.Equal(t, fen, pos.GetFenString())
+	}
+}
@@ -1,100 +1,140 @@
 package evaluate
 
-var pawnBonusBlack = [64]int{
-	0, 0, 0, 0, 0, 0, 0, 0,
-	50, 50, 50, 50, 50, 50, 50, 50,
-	10, 10, 20, 30, 30, 20, 10, 10,
-	5, 5, 10, 27, 27, 10, 5, 5,
-	0, 0, 0, 25, 25, 0, 0, 0,
-	5, -5, -10, 0, 0, -10, -5, 5,
-	5, 10, 10, -25, -25, 10, 10, 5,
-	0, 0, 0, 0, 0, 0, 0, 0,
-}
+import "github.com/tonyOreglia/glee/pkg/position"
 
-var pawnBonusWhite = [64]int{
-	0, 0, 0, 0, 0, 0, 0, 0,
-	5, 10, 10, -25, -25, 10, 10, 5,
-	5, -5, -10, 0, 0, -10, -5, 5,
-	0, 0, 0, 25, 25, 0, 0, 0,
-	5, 5, 10, 27, 27, 10, 5, 5,
-	10, 10, 20, 30, 30, 20, 10, 10,
-	50, 50, 50, 50, 50, 50, 50, 50,
-	0, 0, 0, 0, 0, 0, 0, 0,
-}
+// material values in the middlegame and the endgame, indexed by piece
+var (
+	midgameValues = [7]int{position.King: 20000, position.Queen: 890, position.Rooks: 510, position.Bishops: 330, position.Knights: 320, position.Pawns: 100}
+	endgameValues = [7]int{position.King: 20000, position.Queen: 920, position.Rooks: 540, position.Bishops: 330, position.Knights: 300, position.Pawns: 120}
+)
 
-var knightBonus = [64]int{
-	-50, -40, -30, -30, -30, -30, -40, -50,
-	-40, -20, 0, 0, 0, 0, -20, -40,
-	-30, 0, 10, 15, 15, 10, 0, -30,
-	-30, 5, 15, 20, 20, 15, 5, -30,
-	-30, 0, 15, 20, 20, 15, 0, -30,
-	-30, 5, 10, 15, 15, 10, 5, -30,
-	-40, -20, 0, 5, 5, 0, -20, -40,
-	-50, -40, -20, -30, -30, -20, -40, -50,
-}
-
-var bishopBonusBlack = [64]int{
-	-20, -10, -10, -10, -10, -10, -10, -20,
-	-10, 0, 0, 0, 0, 0, 0, -10,
-	-10, 0, 5, 10, 10, 5, 0, -10,
-	-10, 5, 5, 10, 10, 5, 5, -10,
-	-10, 0, 10, 10, 10, 10, 0, -10,
-	-10, 10, 10, 0, 0, 10, 10, -10,
-	-10, 5, 0, 0, 0, 0, 5, -10,
-	-20, -10, -30, -10, -10, -30, -10, -20,
-}
+// The piece-square tables are laid out as the board is printed from white's side,
+// the first row is the eighth rank. Black looks them up mirrored.
 
-var bishopBonusWhite = [64]int{
-	-20, -10, -30, -10, -10, -30, -10, -20,
-	-10, 5, 0, 0, 0, 0, 5, -10,
-	-10, 10, 10, 0, 0, 10, 10, -10,
-	-10, 0, 10, 10, 10, 10, 0, -10,
-	-10, 5, 5, 10, 10, 5, 5, -10,
-	-10, 0, 5, 10, 10, 5, 0, -10,
-	-10, 0, 0, 0, 0, 0, 0, -10,
-	-20, -10, -10, -10, -10, -10, -10, -20,
+var midgameTables = [7][64]int{
+	position.King: {
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-30, -40, -40, -50, -50, -40, -40, -30,
+		-20, -30, -30, -40, -40, -30, -30, -20,
+		-10, -20, -20, -20, -20, -20, -20, -10,
+		20, 20, 0, 0, 0, 0, 20, 20,
+		20, 30, 25, 0, 0, 10, 30, 20,
+	},
+	position.Queen: {
+		-20, -10, -10, -5, -5, -10, -10, -20,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-10, 0, 5, 5, 5, 5, 0, -10,
+		-5, 0, 5, 5, 5, 5, 0, -5,
+		0, 0, 5, 5, 5, 5, 0, -5,
+		-10, 5, 5, 5, 5, 5, 0, -10,
+		-10, 0, 5, 0, 0, 0, 0, -10,
+		-20, -10, -10, -5, -5, -10, -10, -20,
+	},
+	position.Rooks: {
+		0, 0, 0, 0, 0, 0, 0, 0,
+		5, 10, 10, 10, 10, 10, 10, 5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		-5, 0, 0, 0, 0, 0, 0, -5,
+		0, 0, 0, 5, 5, 0, 0, 0,
+	},
+	position.Bishops: {
+		-20, -10, -10, -10, -10, -10, -10, -20,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-10, 0, 5, 10, 10, 5, 0, -10,
+		-10, 5, 5, 10, 10, 5, 5, -10,
+		-10, 0, 10, 10, 10, 10, 0, -10,
+		-10, 10, 10, 0, 0, 10, 10, -10,
+		-10, 5, 0, 0, 0, 0, 5, -10,
+		-20, -10, -30, -10, -10, -30, -10, -20,
+	},
+	position.Knights: {
+		-50, -40, -30, -30, -30, -30, -40, -50,
+		-40, -20, 0, 0, 0, 0, -20, -40,
+		-30, 0, 10, 15, 15, 10, 0, -30,
+		-30, 5, 15, 20, 20, 15, 5, -30,
+		-30, 0, 15, 20, 20, 15, 0, -30,
+		-30, 5, 10, 15, 15, 10, 5, -30,
+		-40, -20, 0, 5, 5, 0, -20, -40,
+		-50, -40, -20, -30, -30, -20, -40, -50,
+	},
+	position.Pawns: {
+		0, 0, 0, 0, 0, 0, 0, 0,
+		50, 50, 50, 50, 50, 50, 50, 50,
+		10, 10, 20, 30, 30, 20, 10, 10,
+		5, 5, 10, 27, 27, 10, 5, 5,
+		0, 0, 0, 25, 25, 0, 0, 0,
+		5, -5, -10, 0, 0, -10, -5, 5,
+		5, 10, 10, -25, -25, 10, 10, 5,
+		0, 0, 0, 0, 0, 0, 0, 0,
+	},
 }
 
-var kingBonusBlack = [64]int{
-	-30, -40, -40, -50, -50, -40, -40, -30,
-	-30, -40, -40, -50, -50, -40, -40, -30,
-	-30, -40, -40, -50, -50, -40, -40, -30,
-	-30, -40, -40, -50, -50, -40, -40, -30,
-	-20, -30, -30, -40, -40, -30, -30, -20,
-	-10, -20, -20, -20, -20, -20, -20, -10,
-	20, 20, 0, 0, 0, 0, 20, 20,
-	20, 30, 25, 0, 0, 10, 30, 20,
+// in the endgame the king joins the fight, pawns are worth more the closer they are to promotion
+// and the pieces are wanted in the centre rather than developed
+var endgameTables = [7][64]int{
+	position.King: {
+		-50, -40, -30, -20, -20, -30, -40, -50,
+		-30, -20, -10, 0, 0, -10, -20, -30,
+		-30, -10, 20, 30, 30, 20, -10, -30,
+		-30, -10, 30, 40, 40, 30, -10, -30,
+		-30, -10, 30, 40, 40, 30, -10, -30,
+		-30, -10, 20, 30, 30, 20, -10, -30,
+		-30, -30, 0, 0, 0, 0, -30, -30,
+		-50, -30, -30, -30, -30, -30, -30, -50,
+	},
+	position.Queen: {
+		-20, -10, -10, -10, -10, -10, -10, -20,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-10, 0, 10, 10, 10, 10, 0, -10,
+		-10, 0, 10, 20, 20, 10, 0, -10,
+		-10, 0, 10, 20, 20, 10, 0, -10,
+		-10, 0, 10, 10, 10, 10, 0, -10,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-20, -10, -10, -10, -10, -10, -10, -20,
+	},
+	position.Rooks: {
+		0, 0, 0, 0, 0, 0, 0, 0,
+		10, 10, 10, 10, 10, 10, 10, 10,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+	},
+	position.Bishops: {
+		-20, -10, -10, -10, -10, -10, -10, -20,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-10, 0, 5, 10, 10, 5, 0, -10,
+		-10, 0, 10, 15, 15, 10, 0, -10,
+		-10, 0, 10, 15, 15, 10, 0, -10,
+		-10, 0, 5, 10, 10, 5, 0, -10,
+		-10, 0, 0, 0, 0, 0, 0, -10,
+		-20, -10, -10, -10, -10, -10, -10, -20,
+	},
+	position.Knights: {
+		-50, -40, -30, -30, -30, -30, -40, -50,
+		-40, -20, 0, 0, 0, 0, -20, -40,
+		-30, 0, 10, 15, 15, 10, 0, -30,
+		-30, 0, 15, 20, 20, 15, 0, -30,
+		-30, 0, 15, 20, 20, 15, 0, -30,
+		-30, 0, 10, 15, 15, 10, 0, -30,
+		-40, -20, 0, 0, 0, 0, -20, -40,
+		-50, -40, -30, -30, -30, -30, -40, -50,
+	},
+	position.Pawns: {
+		0, 0, 0, 0, 0, 0, 0, 0,
+		80, 80, 80, 80, 80, 80, 80, 80,
+		50, 50, 50, 50, 50, 50, 50, 50,
+		30, 30, 30, 30, 30, 30, 30, 30,
+		15, 15, 15, 15, 15, 15, 15, 15,
+		5, 5, 5, 5, 5, 5, 5, 5,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0,
+	},
 }
-
-var kingBonusWhite = [64]int{
-	20, 30, 25, 0, 0, 10, 30, 20,
-	20, 20, 0, 0, 0, 0, 20, 20,
-	-10, -20, -20, -20, -20, -20, -20, -10,
-	-20, -30, -30, -40, -40, -30, -30, -20,
-	-30, -40, -40, -50, -50, -40, -40, -30,
-	-30, -40, -40, -50, -50, -40, -40, -30,
-	-30, -40, -40, -50, -50, -40, -40, -30,
-	-30, -40, -40, -50, -50, -40, -40, -30,
-}
-
-// var kingEndgameBonusBlack = [64]int{
-// 	-50, -40, -30, -20, -20, -30, -40, -50,
-// 	-30, -20, -10, 0, 0, -10, -20, -30,
-// 	-30, -10, 20, 30, 30, 20, -10, -30,
-// 	-30, -10, 30, 40, 40, 30, -10, -30,
-// 	-30, -10, 30, 40, 40, 30, -10, -30,
-// 	-30, -10, 20, 30, 30, 20, -10, -30,
-// 	-30, -30, 0, 0, 0, 0, -30, -30,
-// 	-50, -30, -30, -30, -30, -30, -30, -50,
-// }
-
-// var kingEndgameBonusWhite = [64]int{
-// 	-50, -30, -30, -30, -30, -30, -30, -50,
-// 	-30, -30, 0, 0, 0, 0, -30, -30,
-// 	-30, -10, 20, 30, 30, 20, -10, -30,
-// 	-30, -10, 30, 40, 40, 30, -10, -30,
-// 	-30, -10, 30, 40, 40, 30, -10, -30,
-// 	-30, -10, 20, 30, 30, 20, -10, -30,
-// 	-30, -20, -10, 0, 0, -10, -20, -30,
-// 	-50, -40, -30, -20, -20, -30, -40, -50,
-// }
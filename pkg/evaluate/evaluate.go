@@ -1,75 +1,98 @@
 package evaluate
 
 import (
+	"math/bits"
+
+	"github.com/tonyOreglia/glee/pkg/bitboard"
 	"github.com/tonyOreglia/glee/pkg/position"
 )
 
-func EvaluatePosition(pos *position.Position) int {
-	score := 0
+// totalPhase is the game phase of the starting position, it falls to 0 as pieces are traded
+const totalPhase = 24
 
-	whiteKingBb := pos.GetWhiteBitboards()[position.King]
-	whiteQueenBb := pos.GetWhiteBitboards()[position.Queen]
-	whiteRooksBb := pos.GetWhiteBitboards()[position.Rooks]
-	whiteKnightsBb := pos.GetWhiteBitboards()[position.Knights]
-	whiteBishopsBb := pos.GetWhiteBitboards()[position.Bishops]
-	whitePawnsBb := pos.GetWhiteBitboards()[position.Pawns]
-	blackKingBb := pos.GetBlackBitboards()[position.King]
-	blackQueensBb := pos.GetBlackBitboards()[position.Queen]
-	blackRooksBb := pos.GetBlackBitboards()[position.Rooks]
-	blackKnightsBb := pos.GetBlackBitboards()[position.Knights]
-	blackBishopsBb := pos.GetBlackBitboards()[position.Bishops]
-	blackPawnsBb := pos.GetBlackBitboards()[position.Pawns]
+// phaseWeights tells how much each piece counts towards the game phase, pawns and kings do not
+var phaseWeights = [7]int{position.Queen: 4, position.Rooks: 2, position.Bishops: 1, position.Knights: 1}
 
-	score += 20000 * (whiteKingBb.PopulationCount() - blackKingBb.PopulationCount())
-	score += 510 * (whiteRooksBb.PopulationCount() - blackRooksBb.PopulationCount())
-	score += 320 * (whiteKnightsBb.PopulationCount() - blackKnightsBb.PopulationCount())
-	score += 330 * (whiteBishopsBb.PopulationCount() - blackBishopsBb.PopulationCount())
-	score += 100 * (whitePawnsBb.PopulationCount() - blackPawnsBb.PopulationCount())
-	score += 890 * (whiteQueenBb.PopulationCount() - blackQueensBb.PopulationCount())
+// bishopPairBonus is given to a side with two bishops or more
+const bishopPairBonus = 15
 
-	score += kingBonusWhite[whiteKingBb.Msb()]
-	score -= kingBonusBlack[blackKingBb.Msb()]
+// MaxScore bounds the evaluation, so that no material advantage scores like a mate found by the search
+const MaxScore = 8800
 
-	if whiteBishopsBb.PopulationCount() > 1 {
-		score += 15
-	}
+// EvaluatePosition scores the position in centipawns from the point of view of the side to move.
+// Material, piece placement, pawn structure, mobility and king safety are scored twice, for the middlegame
+// and for the endgame, and the two scores are blended by the game phase of the position (tapered evaluation).
+func EvaluatePosition(pos *position.Position) int {
+	return Evaluate(pos, nil)
+}
 
-	if blackBishopsBb.PopulationCount() > 1 {
-		score -= 15
+// Evaluate scores the position like EvaluatePosition, looking up the pawn structure in pawns.
+// A nil table evaluates the pawn structure every time.
+func Evaluate(pos *position.Position, pawns *PawnTable) int {
+	var midgame, endgame [2]int
+	for side, pieces := range [2][]bitboard.Bitboard{pos.GetWhiteBitboards(), pos.GetBlackBitboards()} {
+		// black looks up the tables mirrored, flipping the rank of a square
+		mirror := 0
+		if side == position.Black {
+			mirror = 56
+		}
+		for piece := position.King; piece <= position.Pawns; piece++ {
+			bb := pieces[piece].Value()
+			count := bits.OnesCount64(bb)
+			midgame[side] += midgameValues[piece] * count
+			endgame[side] += endgameValues[piece] * count
+			for bb != 0 {
+				sq := bits.TrailingZeros64(bb) ^ mirror
+				bb &= bb - 1
+				midgame[side] += midgameTables[piece][sq]
+				endgame[side] += endgameTables[piece][sq]
+			}
+		}
+		if bits.OnesCount64(pieces[position.Bishops].Value()) > 1 {
+			midgame[side] += bishopPairBonus
+			endgame[side] += bishopPairBonus
+		}
 	}
+	midgameScore := midgame[position.White] - midgame[position.Black]
+	endgameScore := endgame[position.White] - endgame[position.Black]
 
-	for !whiteBishopsBb.IsZero() {
-		msb := whiteBishopsBb.Msb()
-		score += bishopBonusWhite[msb]
-		whiteBishopsBb.RemoveBit(msb)
-	}
-	for !blackBishopsBb.IsZero() {
-		msb := blackBishopsBb.Msb()
-		score -= bishopBonusBlack[msb]
-		blackBishopsBb.RemoveBit(msb)
-	}
+	structure := pawns.probe(pos)
+	passedMidgame, passedEndgame := evaluatePassedPawns(pos, structure.passed)
+	midgameScore += structure.midgame + passedMidgame
+	endgameScore += structure.endgame + passedEndgame
+
+	mobilityMidgame, mobilityEndgame := evaluateMobility(pos)
+	midgameScore += mobilityMidgame + evaluateKingSafety(pos)
+	endgameScore += mobilityEndgame
 
-	for !whiteKnightsBb.IsZero() {
-		msb := whiteKnightsBb.Msb()
-		score += knightBonus[msb]
-		whiteKnightsBb.RemoveBit(msb)
+	score := taper(midgameScore, endgameScore, gamePhase(pos))
+	if score > MaxScore {
+		score = MaxScore
+	} else if score < -MaxScore {
+		score = -MaxScore
 	}
-	for !blackKnightsBb.IsZero() {
-		msb := blackKnightsBb.Msb()
-		score -= knightBonus[msb]
-		blackKnightsBb.RemoveBit(msb)
+	if !pos.IsWhitesTurn() {
+		return -score
 	}
+	return score
+}
 
-	for !whitePawnsBb.IsZero() {
-		msb := whitePawnsBb.Msb()
-		score += pawnBonusWhite[msb]
-		whitePawnsBb.RemoveBit(msb)
+// gamePhase returns totalPhase while all pieces are on the board, down to 0 when only kings and pawns are left
+func gamePhase(pos *position.Position) int {
+	phase := 0
+	for _, pieces := range [2][]bitboard.Bitboard{pos.GetWhiteBitboards(), pos.GetBlackBitboards()} {
+		for piece, weight := range phaseWeights {
+			phase += weight * bits.OnesCount64(pieces[piece].Value())
+		}
 	}
-	for !blackPawnsBb.IsZero() {
-		msb := blackPawnsBb.Msb()
-		score -= pawnBonusBlack[msb]
-		blackPawnsBb.RemoveBit(msb)
+	// promotions can add pieces
+	if phase > totalPhase {
+		return totalPhase
 	}
+	return phase
+}
 
-	return score
+// taper blends a middlegame and an endgame score by phase
+func taper(midgame int, endgame int, phase int) int {
+	return (midgame*phase + endgame*(totalPhase-phase)) / totalPhase
 }
@@ -12,11 +12,66 @@ func TestEvaluatePosition(t *testing.T) {
 	score := EvaluatePosition(pos)
 	assert.Equal(t, 0, score)
 
-	pos, _ = position.NewPositionFen("k7/8/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	pos, _ = position.NewPositionFen("k7/8/8/8/8/8/PPPPPPPP/RNBQKBNR w KQ - 0 1")
 	score = EvaluatePosition(pos)
 	assert.True(t, score > 3000)
 
-	pos, _ = position.NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/8/7K w KQkq - 0 1")
+	pos, _ = position.NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/8/7K w kq - 0 1")
 	score = EvaluatePosition(pos)
 	assert.True(t, score < -3000)
+
+	// scores are from the point of view of the side to move
+	pos, _ = position.NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/8/7K b kq - 0 1")
+	assert.Equal(t, -score, EvaluatePosition(pos))
+}
+
+func TestGamePhase(t *testing.T) {
+	tests := map[string]struct {
+		pos      string
+		expected int
+	}{
+		"starting position":     {pos: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", expected: totalPhase},
+		"queens traded":         {pos: "rnb1kbnr/pppppppp/8/8/8/8/PPPPPPPP/RNB1KBNR w KQkq - 0 1", expected: 16},
+		"rook endgame":          {pos: "4k3/5ppp/8/8/8/8/5PPP/3R2K1 w - - 0 1", expected: 2},
+		"pawn endgame":          {pos: "4k3/5ppp/8/8/8/8/5PPP/6K1 w - - 0 1", expected: 0},
+		"promotions are capped": {pos: "k7/8/1QQQQQQQ/8/8/8/8/RNBQKBNR b - - 0 1", expected: totalPhase},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		assert.Equal(t, test.expected, gamePhase(pos), tName)
+	}
+}
+
+func TestTaperedKingPlacement(t *testing.T) {
+	// with only pawns left the king belongs in the centre
+	centre, _ := position.NewPositionFen("4k3/5ppp/8/8/4K3/8/5PPP/8 w - - 0 1")
+	corner, _ := position.NewPositionFen("4k3/5ppp/8/8/8/8/5PPP/6K1 w - - 0 1")
+	assert.True(t, EvaluatePosition(centre) > EvaluatePosition(corner))
+
+	// in the middlegame it hides behind its pawns
+	centre, _ = position.NewPositionFen("rnbqkbnr/pppppppp/8/8/4K3/8/PPPPPPPP/RNBQ1R2 w kq - 0 1")
+	corner, _ = position.NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQ1RK1 w kq - 0 1")
+	assert.True(t, EvaluatePosition(centre) < EvaluatePosition(corner))
+}
+
+func TestEvaluationIsSymmetric(t *testing.T) {
+	// each position and its colour flipped mirror image score the same for the side to move
+	tests := map[string][2]string{
+		"kiwipete":     {"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", "r3k2r/pppbbppp/2n2q1P/1P2p3/3pn3/BN2PNP1/P1PPQPB1/R3K2R b KQkq - 0 1"},
+		"middlegame":   {"r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10", "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 b - - 0 10"},
+		"rook endgame": {"8/5pk1/6p1/8/R7/6P1/5PK1/r7 w - - 0 1", "R7/5pk1/6p1/r7/8/6P1/5PK1/8 b - - 0 1"},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test[0])
+		flipped, _ := position.NewPositionFen(test[1])
+		assert.Equal(t, EvaluatePosition(pos), EvaluatePosition(flipped), tName)
+	}
+}
+
+func TestEvaluationIsBounded(t *testing.T) {
+	// queens promoted on every file outweigh any score below a mate
+	pos, _ := position.NewPositionFen("k7/8/1QQQQQQQ/8/8/8/8/RNBQKBNR b - - 0 1")
+	assert.Equal(t, -MaxScore, EvaluatePosition(pos))
+	pos, _ = position.NewPositionFen("rnbqkbnr/8/8/8/8/1qqqqqqq/8/K7 w - - 0 1")
+	assert.Equal(t, -MaxScore, EvaluatePosition(pos))
 }
@@ -0,0 +1,85 @@
+package evaluate
+
+import (
+	"math/bits"
+
+	"github.com/tonyOreglia/glee/pkg/bitboard"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// attackingPieces are the pieces counted as attackers of the king zone
+var attackingPieces = [4]int{position.Knights, position.Bishops, position.Rooks, position.Queen}
+
+// evaluateKingSafety scores the safety of the white king minus that of the black king, it is a middlegame term
+func evaluateKingSafety(pos *position.Position) int {
+	occupied := pos.AllOccupiedSqsBb().Value()
+	sides := [2][]bitboard.Bitboard{pos.GetWhiteBitboards(), pos.GetBlackBitboards()}
+	score := 0
+	for side, sign := range [2]int{1, -1} {
+		pieces, opponent := sides[side], sides[1-side]
+		kingSq := bits.TrailingZeros64(pieces[position.King].Value())
+		if kingSq == 64 {
+			continue
+		}
+		score += sign * kingShelter(side, kingSq, pieces[position.Pawns].Value(), opponent[position.Pawns].Value())
+		score -= sign * kingAttack(kingSq, opponent, occupied)
+	}
+	return score
+}
+
+// kingShelter scores the pawns in front of the king on side's kingSq, and the files next to it without pawns
+func kingShelter(side int, kingSq int, own uint64, opponent uint64) int {
+	score := 0
+	file := kingSq % 8
+	files := ht.FileMaskBb[file] | ht.AdjacentFilesBb[file]
+	front := ht.ForwardRanksBb[side][kingSq] & files
+	for bb := front & own; bb != 0; bb &= bb - 1 {
+		if distance := rankDistance(kingSq, bits.TrailingZeros64(bb)); distance <= len(params.PawnShield) {
+			score += params.PawnShield[distance-1]
+		}
+	}
+	for bb := front & opponent; bb != 0; bb &= bb - 1 {
+		if distance := rankDistance(kingSq, bits.TrailingZeros64(bb)); distance <= len(params.PawnStorm) {
+			score -= params.PawnStorm[distance-1]
+		}
+	}
+	for f := file - 1; f <= file+1; f++ {
+		if f < 0 || f > 7 || ht.FileMaskBb[f]&own != 0 {
+			continue
+		}
+		if ht.FileMaskBb[f]&opponent == 0 {
+			score -= params.OpenFile
+		} else {
+			score -= params.SemiOpenFile
+		}
+	}
+	return score
+}
+
+// kingAttack weighs the opposing pieces that attack the king zone, the squares around the king and its own
+func kingAttack(kingSq int, opponent []bitboard.Bitboard, occupied uint64) int {
+	zone := ht.LegalKingMovesNoCastlingBbHash[kingSq] | uint64(1)<<uint(kingSq)
+	attackers, weight := 0, 0
+	for _, piece := range attackingPieces {
+		for bb := opponent[piece].Value(); bb != 0; bb &= bb - 1 {
+			if generate.PieceAttacks(piece, bits.TrailingZeros64(bb), occupied)&zone != 0 {
+				attackers++
+				weight += params.AttackerWeights[piece]
+			}
+		}
+	}
+	if attackers >= len(params.AttackerScale) {
+		attackers = len(params.AttackerScale) - 1
+	}
+	return weight * params.AttackerScale[attackers] / 100
+}
+
+// rankDistance returns the number of ranks between two squares
+func rankDistance(a int, b int) int {
+	distance := a/8 - b/8
+	if distance < 0 {
+		return -distance
+	}
+	return distance
+}
@@ -0,0 +1,51 @@
+package evaluate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestKingSafety(t *testing.T) {
+	tests := map[string]struct {
+		safer  string
+		weaker string
+	}{
+		"pawn shield":         {safer: "4k3/8/8/8/8/8/5PPP/6K1 w - - 0 1", weaker: "4k3/8/8/8/8/5PPP/8/6K1 w - - 0 1"},
+		"pawn storm":          {safer: "4k3/8/8/6p1/8/8/5PPP/6K1 w - - 0 1", weaker: "4k3/8/8/8/6p1/8/5PP1/6K1 w - - 0 1"},
+		"open file":           {safer: "4k3/6p1/8/8/8/8/5PP1/6K1 w - - 0 1", weaker: "4k3/8/8/8/8/8/5P2/6K1 w - - 0 1"},
+		"semi-open file":      {safer: "4k3/6p1/8/8/8/8/5PP1/6K1 w - - 0 1", weaker: "4k3/6p1/8/8/8/8/5P2/6K1 w - - 0 1"},
+		"attackers near king": {safer: "2q1k3/8/8/8/8/8/5PPP/5rK1 w - - 0 1", weaker: "4k3/8/8/8/8/6q1/5PPP/5rK1 w - - 0 1"},
+	}
+	for tName, test := range tests {
+		safer, _ := position.NewPositionFen(test.safer)
+		weaker, _ := position.NewPositionFen(test.weaker)
+		assert.True(t, evaluateKingSafety(safer) > evaluateKingSafety(weaker), tName)
+	}
+}
+
+func TestKingAttack(t *testing.T) {
+	// a lone attacker is not counted
+	pos, _ := position.NewPositionFen("6k1/8/8/8/8/8/5PPP/3q2K1 w - - 0 1")
+	assert.Equal(t, 0, kingAttack(62, pos.GetBlackBitboards(), pos.AllOccupiedSqsBb().Value()))
+	// the queen and the knight attack the zone of the white king
+	pos, _ = position.NewPositionFen("6k1/8/8/8/8/5n2/5PPP/3q2K1 w - - 0 1")
+	expected := (params.AttackerWeights[position.Queen] + params.AttackerWeights[position.Knights]) * params.AttackerScale[2] / 100
+	assert.Equal(t, expected, kingAttack(62, pos.GetBlackBitboards(), pos.AllOccupiedSqsBb().Value()))
+}
+
+func TestSetParams(t *testing.T) {
+	defer SetParams(DefaultParams())
+	pos, _ := position.NewPositionFen("4k3/8/8/8/8/8/5PPP/6K1 w - - 0 1")
+	before := EvaluatePosition(pos)
+	tuned := DefaultParams()
+	tuned.PawnShield[0] += 10
+	SetParams(tuned)
+	// three shield pawns, king safety counts in the middlegame only which this pawn endgame is not
+	assert.Equal(t, before, EvaluatePosition(pos))
+	pos, _ = position.NewPositionFen("r3k3/8/8/8/8/8/5PPP/Q5K1 w - - 0 1")
+	withTuned := EvaluatePosition(pos)
+	SetParams(DefaultParams())
+	assert.True(t, withTuned > EvaluatePosition(pos))
+}
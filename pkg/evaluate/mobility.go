@@ -0,0 +1,41 @@
+package evaluate
+
+import (
+	"math/bits"
+
+	"github.com/tonyOreglia/glee/pkg/bitboard"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// mobilityPieces are the pieces whose mobility is scored
+var mobilityPieces = [4]int{position.Knights, position.Bishops, position.Rooks, position.Queen}
+
+// evaluateMobility scores the mobility of the pieces of white minus those of black
+func evaluateMobility(pos *position.Position) (midgame int, endgame int) {
+	occupied := pos.AllOccupiedSqsBb().Value()
+	sides := [2][]bitboard.Bitboard{pos.GetWhiteBitboards(), pos.GetBlackBitboards()}
+	for side, sign := range [2]int{1, -1} {
+		pieces, opponent := sides[side], sides[1-side]
+		// squares attacked by an opposing pawn are no place for a piece
+		area := ^(pieces[position.OccupiedSqs].Value() | pawnAttacks(1-side, opponent[position.Pawns].Value()))
+		for _, piece := range mobilityPieces {
+			for bb := pieces[piece].Value(); bb != 0; bb &= bb - 1 {
+				sq := bits.TrailingZeros64(bb)
+				squares := bits.OnesCount64(generate.PieceAttacks(piece, sq, occupied)&area) - params.MobilityBaseline[piece]
+				midgame += sign * squares * params.Mobility[piece][0]
+				endgame += sign * squares * params.Mobility[piece][1]
+			}
+		}
+	}
+	return midgame, endgame
+}
+
+// pawnAttacks returns the squares attacked by the pawns of side
+func pawnAttacks(side int, pawns uint64) uint64 {
+	attacks := uint64(0)
+	for ; pawns != 0; pawns &= pawns - 1 {
+		attacks |= ht.PawnAttackBbHash[side][bits.TrailingZeros64(pawns)]
+	}
+	return attacks
+}
@@ -0,0 +1,34 @@
+package evaluate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestMobility(t *testing.T) {
+	tests := map[string]struct {
+		better string
+		worse  string
+	}{
+		"centralised knight":            {better: "4k3/8/8/8/4N3/8/8/4K3 w - - 0 1", worse: "4k3/8/8/8/8/8/8/N3K3 w - - 0 1"},
+		"rook on an open file":          {better: "4k3/8/8/8/8/8/PP1P4/2RK4 w - - 0 1", worse: "4k3/8/8/8/8/8/PPP5/2RK4 w - - 0 1"},
+		"squares held by pawns":         {better: "4k3/8/8/8/8/8/8/2B1K3 w - - 0 1", worse: "4k3/8/8/8/5p2/p7/8/2B1K3 w - - 0 1"},
+		"bishop not hemmed in by pawns": {better: "4k3/8/8/8/8/8/3P4/2B1K3 w - - 0 1", worse: "4k3/8/8/8/8/8/1P1P4/2B1K3 w - - 0 1"},
+	}
+	for tName, test := range tests {
+		better, _ := position.NewPositionFen(test.better)
+		worse, _ := position.NewPositionFen(test.worse)
+		betterMidgame, betterEndgame := evaluateMobility(better)
+		worseMidgame, worseEndgame := evaluateMobility(worse)
+		assert.True(t, betterMidgame > worseMidgame, tName)
+		assert.True(t, betterEndgame > worseEndgame, tName)
+	}
+
+	// a knight on e4 reaches 8 squares, 4 more than the baseline
+	pos, _ := position.NewPositionFen("4k3/8/8/8/4N3/8/8/4K3 w - - 0 1")
+	midgame, endgame := evaluateMobility(pos)
+	assert.Equal(t, 4*params.Mobility[position.Knights][0], midgame)
+	assert.Equal(t, 4*params.Mobility[position.Knights][1], endgame)
+}
@@ -0,0 +1,53 @@
+package evaluate
+
+import "github.com/tonyOreglia/glee/pkg/position"
+
+// Params weighs the mobility and king safety terms in centipawns, so that they can be tuned
+// without touching the evaluation. Pairs hold a middlegame and an endgame value,
+// king safety only counts in the middlegame.
+type Params struct {
+	// Mobility is scored per square a piece can go to, indexed by piece. Squares held by
+	// pieces of its side or attacked by opposing pawns do not count.
+	Mobility [7][2]int
+	// MobilityBaseline is the number of squares of a piece that scores zero, indexed by piece
+	MobilityBaseline [7]int
+	// PawnShield rewards pawns of the king's side on its file or the adjacent ones, one and two ranks ahead of it
+	PawnShield [2]int
+	// PawnStorm penalises opposing pawns on those files, one to three ranks ahead of the king
+	PawnStorm [3]int
+	// OpenFile and SemiOpenFile penalise the king's file and the adjacent ones for having no pawns,
+	// or no pawns of the king's side
+	OpenFile     int
+	SemiOpenFile int
+	// AttackerWeights weighs the pieces that attack the squares around the king, indexed by piece
+	AttackerWeights [7]int
+	// AttackerScale is the percentage of the summed attacker weights that is scored as the king
+	// safety penalty, by the number of attackers. A lone attacker is rarely dangerous.
+	AttackerScale [8]int
+}
+
+// DefaultParams returns the weights the evaluation uses unless SetParams changes them
+func DefaultParams() Params {
+	return Params{
+		Mobility: [7][2]int{
+			position.Queen:   {1, 2},
+			position.Rooks:   {2, 4},
+			position.Bishops: {5, 5},
+			position.Knights: {4, 4},
+		},
+		MobilityBaseline: [7]int{position.Queen: 13, position.Rooks: 7, position.Bishops: 6, position.Knights: 4},
+		PawnShield:       [2]int{15, 8},
+		PawnStorm:        [3]int{10, 10, 5},
+		OpenFile:         20,
+		SemiOpenFile:     10,
+		AttackerWeights:  [7]int{position.Queen: 80, position.Rooks: 40, position.Bishops: 20, position.Knights: 20},
+		AttackerScale:    [8]int{0, 0, 50, 75, 88, 94, 97, 99},
+	}
+}
+
+var params = DefaultParams()
+
+// SetParams replaces the weights of the evaluation, it must not be called during a search
+func SetParams(p Params) {
+	params = p
+}
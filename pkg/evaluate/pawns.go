@@ -0,0 +1,164 @@
+package evaluate
+
+import (
+	"math/bits"
+
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/hashtables"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+var ht = hashtables.Lookup
+
+// pawn structure penalties in the middlegame and the endgame
+var (
+	isolatedPenalty = [2]int{10, 15}
+	doubledPenalty  = [2]int{10, 20}
+	backwardPenalty = [2]int{8, 10}
+	// islandPenalty is taken for every group of adjacent pawn files after the first
+	islandPenalty = [2]int{5, 10}
+)
+
+// bonuses by rank counted from the side's own first rank, in the middlegame and the endgame
+var (
+	passedBonus = [2][8]int{{0, 5, 10, 15, 25, 40, 60, 0}, {0, 10, 15, 25, 45, 70, 110, 0}}
+	// freePathBonus is added for a passed pawn whose way to promotion is clear of any piece
+	freePathBonus = [2][8]int{{0, 0, 0, 5, 10, 15, 20, 0}, {0, 0, 5, 10, 20, 35, 60, 0}}
+	// connectedBonus is given to a pawn that stands next to or is defended by a pawn of its side
+	connectedBonus = [2][8]int{{0, 0, 5, 8, 12, 20, 35, 0}, {0, 0, 3, 5, 8, 15, 25, 0}}
+)
+
+// pawnStructure holds the pawn structure terms of both sides, which only depend on the pawns
+type pawnStructure struct {
+	midgame, endgame int
+	// passed holds the passed pawns of each side, their bonus depends on the other pieces too
+	passed [2]uint64
+}
+
+// pawnTableSize is the number of entries of a pawn table, a power of two
+const pawnTableSize = 1 << 14
+
+type pawnEntry struct {
+	key       uint64
+	structure pawnStructure
+}
+
+// PawnTable caches pawn structure scores by the pawn hash of a position.
+// The pawns change far less often than the rest of the position, so nearly every lookup hits.
+// A table is not safe for concurrent use.
+type PawnTable struct {
+	entries []pawnEntry
+}
+
+// NewPawnTable allocates an empty pawn table
+func NewPawnTable() *PawnTable {
+	return &PawnTable{entries: make([]pawnEntry, pawnTableSize)}
+}
+
+// probe returns the pawn structure of pos, from the table when it has been evaluated before
+func (pt *PawnTable) probe(pos *position.Position) pawnStructure {
+	if pt == nil {
+		return evaluatePawnStructure(pos)
+	}
+	key := pos.PawnHash()
+	entry := &pt.entries[key&(pawnTableSize-1)]
+	// a position without pawns hashes to zero, which is also the key of an empty entry
+	if entry.key != key || key == 0 {
+		entry.key = key
+		entry.structure = evaluatePawnStructure(pos)
+	}
+	return entry.structure
+}
+
+// evaluatePawnStructure scores the pawns of white minus those of black, and finds the passed pawns
+func evaluatePawnStructure(pos *position.Position) pawnStructure {
+	var structure pawnStructure
+	pawns := [2]uint64{pos.GetWhiteBitboards()[position.Pawns].Value(), pos.GetBlackBitboards()[position.Pawns].Value()}
+	for side, sign := range [2]int{1, -1} {
+		var score [2]int
+		structure.passed[side], score = sidePawnStructure(side, pawns[side], pawns[1-side])
+		structure.midgame += sign * score[0]
+		structure.endgame += sign * score[1]
+	}
+	return structure
+}
+
+// sidePawnStructure scores the pawns own of side against the opposing pawns, and returns its passed pawns
+func sidePawnStructure(side int, own uint64, opponent uint64) (passed uint64, score [2]int) {
+	forward := -8
+	if side == position.Black {
+		forward = 8
+	}
+	files := 0
+	for bb := own; bb != 0; bb &= bb - 1 {
+		sq := bits.TrailingZeros64(bb)
+		file, rank := sq%8, relativeRank(side, sq)
+		files |= 1 << uint(file)
+		adjacent := ht.AdjacentFilesBb[file] & own
+		ahead := ht.ForwardRanksBb[side][sq]
+		// of doubled pawns, only the front one may be passed
+		doubled := ahead&ht.FileMaskBb[file]&own != 0
+		if ht.PassedPawnMaskBb[side][sq]&opponent == 0 && !doubled {
+			passed |= uint64(1) << uint(sq)
+		}
+		defended := generate.PawnAttackers(own, sq, side) != 0
+		phalanx := adjacent&rankMask(sq) != 0
+		for phase := range score {
+			if doubled {
+				score[phase] -= doubledPenalty[phase]
+			}
+			if defended || phalanx {
+				score[phase] += connectedBonus[phase][rank]
+			}
+		}
+		if adjacent == 0 {
+			score[0] -= isolatedPenalty[0]
+			score[1] -= isolatedPenalty[1]
+			continue
+		}
+		// a backward pawn has no pawn of its side beside or behind it to support its advance,
+		// and it cannot advance itself without being taken by a pawn
+		stop := sq + forward
+		if adjacent&^ahead == 0 && ht.PawnAttackBbHash[side][stop]&opponent != 0 {
+			score[0] -= backwardPenalty[0]
+			score[1] -= backwardPenalty[1]
+		}
+	}
+	if islands := bits.OnesCount(uint(files &^ (files << 1))); islands > 1 {
+		score[0] -= islandPenalty[0] * (islands - 1)
+		score[1] -= islandPenalty[1] * (islands - 1)
+	}
+	return passed, score
+}
+
+// evaluatePassedPawns scores the passed pawns of white minus those of black. A pawn whose
+// way to promotion is clear of pieces of either side is harder to stop.
+func evaluatePassedPawns(pos *position.Position, passed [2]uint64) (midgame int, endgame int) {
+	occupied := pos.AllOccupiedSqsBb().Value()
+	for side, sign := range [2]int{1, -1} {
+		for bb := passed[side]; bb != 0; bb &= bb - 1 {
+			sq := bits.TrailingZeros64(bb)
+			rank := relativeRank(side, sq)
+			midgame += sign * passedBonus[0][rank]
+			endgame += sign * passedBonus[1][rank]
+			if ht.ForwardRanksBb[side][sq]&ht.FileMaskBb[sq%8]&occupied == 0 {
+				midgame += sign * freePathBonus[0][rank]
+				endgame += sign * freePathBonus[1][rank]
+			}
+		}
+	}
+	return midgame, endgame
+}
+
+// relativeRank counts the ranks from the first rank of side, 0 to 7
+func relativeRank(side int, sq int) int {
+	if side == position.White {
+		return 7 - sq/8
+	}
+	return sq / 8
+}
+
+// rankMask returns the rank of sq
+func rankMask(sq int) uint64 {
+	return uint64(0xFF) << uint(sq/8*8)
+}
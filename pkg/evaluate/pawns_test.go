@@ -0,0 +1,93 @@
+package evaluate
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func squares(bb uint64) []string {
+	sqs := []string{}
+	for ; bb != 0; bb &= bb - 1 {
+		sqs = append(sqs, moves.ConvertIndexToAlgebraic(bits.TrailingZeros64(bb)))
+	}
+	return sqs
+}
+
+func TestPassedPawns(t *testing.T) {
+	tests := map[string]struct {
+		pos   string
+		white []string
+		black []string
+	}{
+		"blocked on the same file":     {pos: "4k3/8/8/3p4/8/8/3P4/4K3 w - - 0 1", white: []string{}, black: []string{}},
+		"free pawn":                    {pos: "4k3/8/8/8/3P4/8/8/4K3 w - - 0 1", white: []string{"d4"}, black: []string{}},
+		"guarded by an adjacent file":  {pos: "4k3/2p5/8/3P4/8/8/8/4K3 w - - 0 1", white: []string{}, black: []string{}},
+		"pawns that passed each other": {pos: "4k3/8/8/3P4/2p5/8/8/4K3 w - - 0 1", white: []string{"d5"}, black: []string{"c4"}},
+		"only the front doubled pawn":  {pos: "4k3/8/8/8/3P4/3P4/8/4K3 w - - 0 1", white: []string{"d4"}, black: []string{}},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		structure := evaluatePawnStructure(pos)
+		assert.Equal(t, test.white, squares(structure.passed[position.White]), tName)
+		assert.Equal(t, test.black, squares(structure.passed[position.Black]), tName)
+	}
+}
+
+func TestPawnStructureTerms(t *testing.T) {
+	// scores of the white pawns in the middlegame and endgame
+	tests := map[string]struct {
+		pos      string
+		expected [2]int
+	}{
+		"isolated":          {pos: "4k3/8/8/8/8/8/3P4/4K3 w - - 0 1", expected: [2]int{-10, -15}},
+		"doubled isolated":  {pos: "4k3/8/8/8/8/3P4/3P4/4K3 w - - 0 1", expected: [2]int{-30, -50}},
+		"phalanx":           {pos: "4k3/8/8/8/3PP3/8/8/4K3 w - - 0 1", expected: [2]int{16, 10}},
+		"backward":          {pos: "4k3/8/8/4p3/2P5/3P4/8/4K3 w - - 0 1", expected: [2]int{0, -5}},
+		"four pawn islands": {pos: "4k3/8/8/8/8/8/P1P1P1P1/4K3 w - - 0 1", expected: [2]int{-55, -90}},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		_, score := sidePawnStructure(position.White, pos.GetWhiteBitboards()[position.Pawns].Value(), pos.GetBlackBitboards()[position.Pawns].Value())
+		assert.Equal(t, test.expected, score, tName)
+	}
+}
+
+func TestPassedPawnPath(t *testing.T) {
+	free, _ := position.NewPositionFen("4k3/8/8/3P4/8/8/8/4K3 w - - 0 1")
+	blocked, _ := position.NewPositionFen("4k3/3n4/8/3P4/8/8/8/4K3 w - - 0 1")
+	freeMidgame, freeEndgame := evaluatePassedPawns(free, evaluatePawnStructure(free).passed)
+	blockedMidgame, blockedEndgame := evaluatePassedPawns(blocked, evaluatePawnStructure(blocked).passed)
+	assert.True(t, freeMidgame > blockedMidgame)
+	assert.True(t, freeEndgame > blockedEndgame)
+	assert.True(t, blockedEndgame > 0)
+}
+
+func TestPawnTable(t *testing.T) {
+	pawns := NewPawnTable()
+	fens := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"4k3/8/8/4p3/2P5/3P4/8/4K3 b - - 0 1",
+		"4k3/8/8/8/8/8/8/4K3 w - - 0 1",
+	}
+	for _, fen := range fens {
+		pos, _ := position.NewPositionFen(fen)
+		// the first lookup fills the table, the second one hits
+		assert.Equal(t, EvaluatePosition(pos), Evaluate(pos, pawns), fen)
+		assert.Equal(t, EvaluatePosition(pos), Evaluate(pos, pawns), fen)
+	}
+	pos, _ := position.NewPositionFen(fens[1])
+	assert.Equal(t, pos.PawnHash(), pawns.entries[pos.PawnHash()&(pawnTableSize-1)].key)
+}
+
+func BenchmarkEvaluate(b *testing.B) {
+	pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	pawns := NewPawnTable()
+	for i := 0; i < b.N; i++ {
+		Evaluate(pos, pawns)
+	}
+}
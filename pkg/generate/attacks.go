@@ -0,0 +1,66 @@
+package generate
+
+import (
+	"github.com/tonyOreglia/glee/pkg/bitboard"
+	"github.com/tonyOreglia/glee/pkg/hashtables"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// InCheck reports whether the king of the side to move is attacked
+func InCheck(pos *position.Position) bool {
+	kingBb := pos.ActiveSideKingBb()
+	return IsSquareAttacked(pos, kingBb.Lsb(), 1-pos.GetActiveSide())
+}
+
+// IsSquareAttacked reports whether a piece of bySide attacks sq
+func IsSquareAttacked(pos *position.Position, sq int, bySide int) bool {
+	return AttackersOf(sideBitboards(pos, bySide), sq, bySide, pos.AllOccupiedSqsBb().Value()) != 0
+}
+
+// AttackersOf looks outwards from sq for the pieces of side that attack it, which is cheaper
+// than generating every move of side. pieces are the bitboards of side. Sliding pieces are blocked
+// by the squares in occupied, which need not be the position's so that moves can be tried without
+// making them. Pieces missing from occupied are not removed from the result.
+func AttackersOf(pieces []bitboard.Bitboard, sq int, side int, occupied uint64) uint64 {
+	ht := hashtables.Lookup
+	diagonalAttackers := pieces[position.Bishops].Value() | pieces[position.Queen].Value()
+	straightAttackers := pieces[position.Rooks].Value() | pieces[position.Queen].Value()
+	return PawnAttackers(pieces[position.Pawns].Value(), sq, side) |
+		ht.KnightAttackBbHash[sq]&pieces[position.Knights].Value() |
+		ht.LegalKingMovesNoCastlingBbHash[sq]&pieces[position.King].Value() |
+		ht.BishopAttacks(sq, occupied)&diagonalAttackers |
+		ht.RookAttacks(sq, occupied)&straightAttackers
+}
+
+// PawnAttackers returns the pawns of side among pawns that attack sq.
+// A pawn of side attacks sq from the squares an opposing pawn on sq would attack.
+func PawnAttackers(pawns uint64, sq int, side int) uint64 {
+	return hashtables.Lookup.PawnAttackBbHash[1-side][sq] & pawns
+}
+
+// sideBitboards returns the bitboards of side in pos
+func sideBitboards(pos *position.Position, side int) []bitboard.Bitboard {
+	if side == position.Black {
+		return pos.GetBlackBitboards()
+	}
+	return pos.GetWhiteBitboards()
+}
+
+// PieceAttacks returns the squares a knight, bishop, rook, queen or king on sq attacks,
+// sliding pieces are blocked by the squares in occupied. Pawns are not handled, their attacks depend on their side.
+func PieceAttacks(piece int, sq int, occupied uint64) uint64 {
+	ht := hashtables.Lookup
+	switch piece {
+	case position.Knights:
+		return ht.KnightAttackBbHash[sq]
+	case position.Bishops:
+		return ht.BishopAttacks(sq, occupied)
+	case position.Rooks:
+		return ht.RookAttacks(sq, occupied)
+	case position.Queen:
+		return ht.QueenAttacks(sq, occupied)
+	case position.King:
+		return ht.LegalKingMovesNoCastlingBbHash[sq]
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package generate
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestInCheck(t *testing.T) {
+	tests := map[string]struct {
+		pos     string
+		inCheck bool
+	}{
+		"starting position":            {pos: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", inCheck: false},
+		"white pawn checks":            {pos: "4k3/3P4/8/8/8/8/8/4K3 b - - 0 1", inCheck: true},
+		"black pawn checks":            {pos: "4k3/8/8/8/8/8/5p2/4K3 w - - 0 1", inCheck: true},
+		"pawn does not check backward": {pos: "4k3/8/8/4K3/3p4/8/8/8 w - - 0 1", inCheck: false},
+		"pawn on the a file":           {pos: "4k3/8/8/8/8/8/p7/1K6 w - - 0 1", inCheck: true},
+		"no wrap around the board":     {pos: "4k3/8/8/8/8/7p/K7/8 w - - 0 1", inCheck: false},
+		"knight checks":                {pos: "4k3/8/3N4/8/8/8/8/4K3 b - - 0 1", inCheck: true},
+		"bishop checks":                {pos: "4k3/8/8/8/1b6/8/8/4K3 w - - 0 1", inCheck: true},
+		"bishop blocked":               {pos: "4k3/8/8/8/1b6/2P5/8/4K3 w - - 0 1", inCheck: false},
+		"rook checks":                  {pos: "4k3/8/8/8/8/8/8/r3K3 w - - 0 1", inCheck: true},
+		"queen checks on the file":     {pos: "4k3/8/8/8/8/8/8/4K2q w - - 0 1", inCheck: true},
+		"queen blocked":                {pos: "4k3/4r3/8/8/8/8/4P3/4K3 w - - 0 1", inCheck: false},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		assert.Equal(t, test.inCheck, InCheck(pos), tName)
+	}
+}
+
+func TestIsSquareAttacked(t *testing.T) {
+	tests := map[string]struct {
+		pos      string
+		sq       string
+		bySide   int
+		attacked bool
+	}{
+		"pawn attacks diagonally":       {pos: "4k3/8/8/8/8/8/3P4/4K3 w - - 0 1", sq: "e3", bySide: position.White, attacked: true},
+		"pawn does not attack forward":  {pos: "4k3/8/8/8/8/8/3P4/4K3 w - - 0 1", sq: "d3", bySide: position.White, attacked: false},
+		"either side may be asked":      {pos: "4k3/8/8/8/8/8/3P4/4K3 w - - 0 1", sq: "d7", bySide: position.Black, attacked: true},
+		"rook through an empty rank":    {pos: "4k3/8/8/8/8/8/8/R3K3 w - - 0 1", sq: "a8", bySide: position.White, attacked: true},
+		"rook blocked by its own piece": {pos: "4k3/8/8/8/N7/8/8/R3K3 w - - 0 1", sq: "a8", bySide: position.White, attacked: false},
+		"a defended piece is attacked":  {pos: "4k3/8/8/8/N7/8/8/R3K3 w - - 0 1", sq: "a4", bySide: position.White, attacked: true},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		sq, _ := moves.ConvertAlgebriacToIndex(test.sq)
+		assert.Equal(t, test.attacked, IsSquareAttacked(pos, sq, test.bySide), tName)
+	}
+}
+
+func TestPieceAttacks(t *testing.T) {
+	// a rook on a1 with a blocker on a4
+	occupied := uint64(1)<<56 | uint64(1)<<32
+	tests := map[string]struct {
+		piece    int
+		sq       int
+		expected int
+	}{
+		"knight in the corner":  {piece: position.Knights, sq: 56, expected: 2},
+		"knight in the centre":  {piece: position.Knights, sq: 36, expected: 8},
+		"blocked rook":          {piece: position.Rooks, sq: 56, expected: 10},
+		"bishop in the corner":  {piece: position.Bishops, sq: 56, expected: 7},
+		"blocked queen":         {piece: position.Queen, sq: 56, expected: 17},
+		"king in the corner":    {piece: position.King, sq: 56, expected: 3},
+		"pawns are not handled": {piece: position.Pawns, sq: 52, expected: 0},
+	}
+	for tName, test := range tests {
+		assert.Equal(t, test.expected, bits.OnesCount64(PieceAttacks(test.piece, test.sq, occupied)), tName)
+	}
+}
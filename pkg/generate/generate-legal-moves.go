@@ -0,0 +1,228 @@
+package generate
+
+import (
+	"math/bits"
+
+	"github.com/tonyOreglia/glee/pkg/hashtables"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// GenerateLegalMoves generates the legal moves of a position, so unlike GenerateMoves
+// there is no need to make a move to find out whether it leaves the king in check.
+// Pieces pinned to their king only move along the pin, and in check only moves that
+// capture the checking piece or block its line are generated, besides king moves.
+func GenerateLegalMoves(pos *position.Position) *moves.Moves {
+	mvsList := moves.NewMovesList()
+	ht := hashtables.Lookup
+	lg := newLegalGenerator(pos, ht)
+	lg.addKingMoves(mvsList)
+	// in double check only the king can move
+	if bits.OnesCount64(lg.checkers) > 1 {
+		return mvsList
+	}
+	pieces := pos.GetActiveSidesBitboards()
+	lg.addPieceMoves(mvsList, pieces[position.Knights].Value(), func(sq int) uint64 { return ht.KnightAttackBbHash[sq] })
+	lg.addPieceMoves(mvsList, pieces[position.Bishops].Value(), func(sq int) uint64 { return ht.BishopAttacks(sq, lg.occupied) })
+	lg.addPieceMoves(mvsList, pieces[position.Rooks].Value(), func(sq int) uint64 { return ht.RookAttacks(sq, lg.occupied) })
+	lg.addPieceMoves(mvsList, pieces[position.Queen].Value(), func(sq int) uint64 { return ht.QueenAttacks(sq, lg.occupied) })
+	lg.addPawnMoves(mvsList, pieces[position.Pawns].Value())
+	return mvsList
+}
+
+// legalGenerator holds what the side to move's pieces need to know to move legally
+type legalGenerator struct {
+	pos      *position.Position
+	ht       *hashtables.HashTables
+	side     int
+	kingSq   int
+	occupied uint64
+	own      uint64
+	opponent uint64
+	checkers uint64
+	// checkMask holds the squares a piece other than the king may move to, all of them unless in check
+	checkMask uint64
+	// pinned holds the pieces pinned to their king, pinRays the squares each of them may move to
+	pinned  uint64
+	pinRays [64]uint64
+}
+
+func newLegalGenerator(pos *position.Position, ht *hashtables.HashTables) *legalGenerator {
+	kingBb := pos.ActiveSideKingBb()
+	lg := &legalGenerator{
+		pos:      pos,
+		ht:       ht,
+		side:     pos.GetActiveSide(),
+		kingSq:   kingBb.Lsb(),
+		occupied: pos.AllOccupiedSqsBb().Value(),
+		own:      pos.ActiveSideOccupiedSqsBb().Value(),
+		opponent: pos.InactiveSideOccupiedSqsBb().Value(),
+	}
+	lg.checkers = lg.attackersOf(lg.kingSq, lg.occupied)
+	lg.checkMask = ^uint64(0)
+	if lg.checkers != 0 {
+		checker := bits.TrailingZeros64(lg.checkers)
+		lg.checkMask = lg.checkers | between(lg.kingSq, checker, ht)
+	}
+	lg.findPins()
+	return lg
+}
+
+// attackersOf returns the opposing pieces that attack sq, sliders are blocked by occupied
+func (lg *legalGenerator) attackersOf(sq int, occupied uint64) uint64 {
+	return AttackersOf(sideBitboards(lg.pos, 1-lg.side), sq, 1-lg.side, occupied)
+}
+
+// findPins looks from the king through one piece of its own side for opposing sliders
+func (lg *legalGenerator) findPins() {
+	opponent := sideBitboards(lg.pos, 1-lg.side)
+	queens := opponent[position.Queen].Value()
+	// a slider pins when the king would be attacked if the pieces of its own side were gone
+	pinners := lg.ht.RookAttacks(lg.kingSq, lg.opponent)&(opponent[position.Rooks].Value()|queens) |
+		lg.ht.BishopAttacks(lg.kingSq, lg.opponent)&(opponent[position.Bishops].Value()|queens)
+	for pinners != 0 {
+		pinner := bits.TrailingZeros64(pinners)
+		pinners &= pinners - 1
+		line := between(lg.kingSq, pinner, lg.ht)
+		blockers := line & lg.occupied
+		if bits.OnesCount64(blockers) == 1 && blockers&lg.own != 0 {
+			lg.pinned |= blockers
+			lg.pinRays[bits.TrailingZeros64(blockers)] = line | uint64(1)<<uint(pinner)
+		}
+	}
+}
+
+// allowed returns the squares the piece on sq may move to without leaving its king in check
+func (lg *legalGenerator) allowed(sq int) uint64 {
+	allowed := lg.checkMask &^ lg.own
+	if lg.pinned&(uint64(1)<<uint(sq)) != 0 {
+		allowed &= lg.pinRays[sq]
+	}
+	return allowed
+}
+
+// addPieceMoves adds the legal moves of knights or sliders, attacks returns the squares a piece on sq attacks
+func (lg *legalGenerator) addPieceMoves(mvsList *moves.Moves, pieces uint64, attacks func(sq int) uint64) {
+	for pieces != 0 {
+		sq := bits.TrailingZeros64(pieces)
+		pieces &= pieces - 1
+		addMovesFromBb(mvsList, sq, attacks(sq)&lg.allowed(sq))
+	}
+}
+
+// castling squares of each side: the king's destination, the squares that must be empty
+// and the square the king crosses, which must not be attacked
+var castlings = [2][2]struct {
+	allowed     func(*position.Position) bool
+	dest        int
+	crossed     int
+	mustBeEmpty uint64
+}{
+	position.White: {
+		{(*position.Position).WhiteCanCastleKingSide, 62, 61, uint64(3) << 61},
+		{(*position.Position).WhiteCanCastleQueenSide, 58, 59, uint64(7) << 57},
+	},
+	position.Black: {
+		{(*position.Position).BlackCanCastleKingSide, 6, 5, uint64(3) << 5},
+		{(*position.Position).BlackCanCastleQueenSide, 2, 3, uint64(7) << 1},
+	},
+}
+
+// addKingMoves adds the king moves to squares that are not attacked, castling included
+func (lg *legalGenerator) addKingMoves(mvsList *moves.Moves) {
+	// the king does not block attacks on the squares behind it
+	withoutKing := lg.occupied &^ (uint64(1) << uint(lg.kingSq))
+	targets := lg.ht.LegalKingMovesNoCastlingBbHash[lg.kingSq] &^ lg.own
+	for targets != 0 {
+		dest := bits.TrailingZeros64(targets)
+		targets &= targets - 1
+		if lg.attackersOf(dest, withoutKing) == 0 {
+			mvsList.AddMove(lg.kingSq, dest)
+		}
+	}
+	if lg.checkers != 0 {
+		return
+	}
+	for _, castling := range castlings[lg.side] {
+		if castling.allowed(lg.pos) && lg.occupied&castling.mustBeEmpty == 0 &&
+			lg.attackersOf(castling.crossed, lg.occupied) == 0 &&
+			lg.attackersOf(castling.dest, lg.occupied) == 0 {
+			mvsList.AddMove(lg.kingSq, castling.dest)
+		}
+	}
+}
+
+// addPawnMoves adds pushes, captures, en passante captures and promotions of the pawns
+func (lg *legalGenerator) addPawnMoves(mvsList *moves.Moves, pawns uint64) {
+	forward, startRank, promotionRank := -8, 6, 0
+	if lg.side == position.Black {
+		forward, startRank, promotionRank = 8, 1, 7
+	}
+	epSq := lg.pos.EnPassante()
+	for pawns != 0 {
+		sq := bits.TrailingZeros64(pawns)
+		pawns &= pawns - 1
+		targets := lg.ht.PawnAttackBbHash[lg.side][sq] & lg.opponent
+		push := sq + forward
+		if lg.occupied&(uint64(1)<<uint(push)) == 0 {
+			targets |= uint64(1) << uint(push)
+			doublePush := push + forward
+			if sq/8 == startRank && lg.occupied&(uint64(1)<<uint(doublePush)) == 0 {
+				targets |= uint64(1) << uint(doublePush)
+			}
+		}
+		targets &= lg.allowed(sq)
+		for targets != 0 {
+			dest := bits.TrailingZeros64(targets)
+			targets &= targets - 1
+			if dest/8 == promotionRank {
+				mvsList.AddPromotionMove(sq, dest, position.Queen)
+				mvsList.AddPromotionMove(sq, dest, position.Rooks)
+				mvsList.AddPromotionMove(sq, dest, position.Knights)
+				mvsList.AddPromotionMove(sq, dest, position.Bishops)
+			} else {
+				mvsList.AddMove(sq, dest)
+			}
+		}
+		if epSq != 64 && lg.ht.PawnAttackBbHash[lg.side][sq]&(uint64(1)<<uint(epSq)) != 0 && lg.enPassanteIsLegal(sq, epSq, epSq-forward) {
+			mvsList.AddMove(sq, epSq)
+		}
+	}
+}
+
+// enPassanteIsLegal tries the capture on the board occupancy. Two pawns leave the rank at once,
+// which can expose the king in ways a pin mask does not describe.
+func (lg *legalGenerator) enPassanteIsLegal(origin int, dest int, capturedSq int) bool {
+	capturedBb := uint64(1) << uint(capturedSq)
+	destBb := uint64(1) << uint(dest)
+	// in check, the capture has to remove the checking pawn or block the check
+	if lg.checkMask&(capturedBb|destBb) == 0 {
+		return false
+	}
+	occupied := lg.occupied&^(uint64(1)<<uint(origin))&^capturedBb | destBb
+	opponent := sideBitboards(lg.pos, 1-lg.side)
+	queens := opponent[position.Queen].Value()
+	return lg.ht.RookAttacks(lg.kingSq, occupied)&(opponent[position.Rooks].Value()|queens) == 0 &&
+		lg.ht.BishopAttacks(lg.kingSq, occupied)&(opponent[position.Bishops].Value()|queens) == 0
+}
+
+// between returns the squares strictly between a and b when they share a line, otherwise none
+func between(a int, b int, ht *hashtables.HashTables) uint64 {
+	aBb, bBb := uint64(1)<<uint(a), uint64(1)<<uint(b)
+	if ht.RookAttacks(a, 0)&bBb != 0 {
+		return ht.RookAttacks(a, bBb) & ht.RookAttacks(b, aBb)
+	}
+	if ht.BishopAttacks(a, 0)&bBb != 0 {
+		return ht.BishopAttacks(a, bBb) & ht.BishopAttacks(b, aBb)
+	}
+	return 0
+}
+
+// addMovesFromBb adds a move from origin to every square of destinations
+func addMovesFromBb(mvsList *moves.Moves, origin int, destinations uint64) {
+	for destinations != 0 {
+		dest := bits.TrailingZeros64(destinations)
+		destinations &= destinations - 1
+		mvsList.AddMove(origin, dest)
+	}
+}
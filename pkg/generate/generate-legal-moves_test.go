@@ -0,0 +1,71 @@
+package generate_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/engine"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// movesByMakeValidMove lists the pseudo legal moves that engine.MakeValidMove accepts
+func movesByMakeValidMove(pos *position.Position) []string {
+	legal := []string{}
+	for _, move := range generate.GenerateMoves(pos).GetMovesList() {
+		if engine.MakeValidMove(move, &pos) {
+			pos.UnMakeMove()
+			legal = append(legal, move.String())
+		}
+	}
+	sort.Strings(legal)
+	return legal
+}
+
+func legalMoveStrings(pos *position.Position) []string {
+	legal := []string{}
+	for _, move := range generate.GenerateLegalMoves(pos).GetMovesList() {
+		legal = append(legal, move.String())
+	}
+	sort.Strings(legal)
+	return legal
+}
+
+// checkLegalMoves walks the move tree comparing both ways of finding legal moves at every node
+func checkLegalMoves(t *testing.T, pos *position.Position, depth int) {
+	legal := legalMoveStrings(pos)
+	if !assert.Equal(t, movesByMakeValidMove(pos), legal, pos.GetFenString()) || depth == 0 {
+		return
+	}
+	for _, move := range generate.GenerateLegalMoves(pos).GetMovesList() {
+		pos.Move(move)
+		checkLegalMoves(t, pos, depth-1)
+		pos.UnMakeMove()
+	}
+}
+
+func TestGenerateLegalMoves(t *testing.T) {
+	tests := map[string]struct {
+		fen   string
+		depth int
+	}{
+		"starting position":        {"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", 2},
+		"kiwipete":                 {"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 2},
+		"pins and en passante":     {"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 3},
+		"promotions and checks":    {"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 2},
+		"position 5":               {"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", 2},
+		"en passante exposes king": {"8/8/8/K2pP2r/8/8/8/7k w - d6 0 1", 1},
+		"double check":             {"4k3/8/8/8/8/5n2/8/r3K2R w K - 0 1", 1},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.fen)
+		t.Run(tName, func(t *testing.T) {
+			checkLegalMoves(t, pos, test.depth)
+		})
+	}
+
+	// the pawn on e5 cannot take en passante, it would uncover the rook's check along the rank
+	pos, _ := position.NewPositionFen("8/8/8/K2pP2r/8/8/8/7k w - d6 0 1")
+	assert.NotContains(t, legalMoveStrings(pos), "e5d6")
+}
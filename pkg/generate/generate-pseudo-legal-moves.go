@@ -29,6 +29,44 @@ func GenerateAllMoves(pos *position.Position, mvsList *moves.Moves, ht *hashtabl
 	GenerateBishopMoves(pos, mvsList, ht)
 }
 
+// GenerateCaptures generates the pseudo legal captures and promotions of a position,
+// the moves that can still change the material balance at the end of a search.
+func GenerateCaptures(pos *position.Position) *moves.Moves {
+	movesList := moves.NewMovesList()
+	ht := hashtables.Lookup
+	GenerateAllCaptures(pos, movesList, ht)
+	return movesList
+}
+
+// GenerateAllCaptures adds captures, including en passante, and pawn promotions to the move list.
+// Like GenerateAllMoves, captures that leave the king in check are included.
+func GenerateAllCaptures(pos *position.Position, mvsList *moves.Moves, ht *hashtables.HashTables) {
+	GeneratePawnCaptures(pos, mvsList, ht)
+	targetsBb := pos.InactiveSideOccupiedSqsBb()
+	kingBb := pos.GetActiveSidesBitboards()[position.King]
+	kingPosition := kingBb.Lsb()
+	kingCapturesBb := bitboard.NewBitboard(ht.LegalKingMovesNoCastlingBbHash[kingPosition]).BitwiseAnd(targetsBb)
+	addValidMovesToArray(mvsList, kingPosition, kingCapturesBb)
+	pieces := pos.GetActiveSidesBitboards()
+	generateCapturesForSinglePiece(pos, mvsList, pieces[position.Queen].Value(), getQueenMovesBb, ht)
+	generateCapturesForSinglePiece(pos, mvsList, pieces[position.Rooks].Value(), getRookMovesBb, ht)
+	generateCapturesForSinglePiece(pos, mvsList, pieces[position.Knights].Value(), getKnightMovesBb, ht)
+	generateCapturesForSinglePiece(pos, mvsList, pieces[position.Bishops].Value(), getBishopMovesBb, ht)
+}
+
+func generateCapturesForSinglePiece(
+	pos *position.Position, movesList *moves.Moves, pieceLocationsBb uint64, genValidMovesFn func(int, uint64, *hashtables.HashTables) *bitboard.Bitboard, ht *hashtables.HashTables) {
+
+	pieceLocationBbCopy := bitboard.NewBitboard(pieceLocationsBb)
+	for pieceLocationBbCopy.Value() != 0 {
+		piecePosition := pieceLocationBbCopy.Lsb()
+		pieceLocationBbCopy.RemoveBit(piecePosition)
+		validMovesBb := genValidMovesFn(piecePosition, pos.AllOccupiedSqsBb().Value(), ht)
+		validMovesBb.BitwiseAnd(pos.InactiveSideOccupiedSqsBb())
+		addValidMovesToArray(movesList, piecePosition, validMovesBb)
+	}
+}
+
 func generateLegalMovesForSinglePiece(
 	pos *position.Position, movesList *moves.Moves, pieceLocationsBb uint64, genValidMovesFn func(int, uint64, *hashtables.HashTables) *bitboard.Bitboard, ht *hashtables.HashTables) {
 
@@ -44,15 +82,15 @@ func generateLegalMovesForSinglePiece(
 }
 
 func GenerateBishopMoves(pos *position.Position, mvsList *moves.Moves, ht *hashtables.HashTables) {
-	generateLegalMovesForSinglePiece(pos, mvsList, pos.GetActiveSidesBitboards()[position.Bishops].Value(), generateValidDiagonalSlidingMovesBb, ht)
+	generateLegalMovesForSinglePiece(pos, mvsList, pos.GetActiveSidesBitboards()[position.Bishops].Value(), getBishopMovesBb, ht)
 }
 
 func GenerateRookMoves(pos *position.Position, mvsList *moves.Moves, ht *hashtables.HashTables) {
-	generateLegalMovesForSinglePiece(pos, mvsList, pos.GetActiveSidesBitboards()[position.Rooks].Value(), generateValidStraightSlidingMovesBb, ht)
+	generateLegalMovesForSinglePiece(pos, mvsList, pos.GetActiveSidesBitboards()[position.Rooks].Value(), getRookMovesBb, ht)
 }
 
 func GenerateQueenMoves(pos *position.Position, mvsList *moves.Moves, ht *hashtables.HashTables) {
-	generateLegalMovesForSinglePiece(pos, mvsList, pos.GetActiveSidesBitboards()[position.Queen].Value(), generateSlidingMovesBb, ht)
+	generateLegalMovesForSinglePiece(pos, mvsList, pos.GetActiveSidesBitboards()[position.Queen].Value(), getQueenMovesBb, ht)
 }
 
 func GenerateKnightMoves(pos *position.Position, mvsList *moves.Moves, ht *hashtables.HashTables) {
@@ -196,6 +234,49 @@ func GeneratePawnMoves(pos *position.Position, mvsList *moves.Moves, ht *hashtab
 	addPawnMovesToArray(mvsList, 16, directionOfMovement, doubleRankpawnPushBb, promotionRank)
 }
 
+// GeneratePawnCaptures adds pawn captures, including en passante, and promotions to the move list
+func GeneratePawnCaptures(pos *position.Position, mvsList *moves.Moves, ht *hashtables.HashTables) {
+	var getShiftedBb func(*bitboard.Bitboard, uint) *bitboard.Bitboard
+	var directionOfMovement int
+	var promotionRank *bitboard.Bitboard
+	var attackRightShift uint
+	var attackLeftShift uint
+	enPassanteBB := bitboard.NewBitboardFromIndex(pos.EnPassante())
+	if pos.GetActiveSide() == position.White {
+		getShiftedBb = bitboard.GetShiftedRightBb
+		directionOfMovement = 1
+		promotionRank = bitboard.NewBitboard(ht.EighthRankBb)
+		attackRightShift = 7
+		attackLeftShift = 9
+	} else {
+		getShiftedBb = bitboard.GetShiftedLeftBb
+		directionOfMovement = -1
+		promotionRank = bitboard.NewBitboard(ht.FirstRankBb)
+		attackRightShift = 9
+		attackLeftShift = 7
+	}
+
+	pawnPosBb := pos.GetActiveSidesBitboards()[position.Pawns]
+	hFileBb := bitboard.NewBitboard(ht.HfileBb)
+	aFileBb := bitboard.NewBitboard(ht.AfileBb)
+
+	pawnAttackBb := getShiftedBb(&pawnPosBb, attackLeftShift).
+		RemoveOverlappingBits(hFileBb).
+		BitwiseAnd(bitboard.ReturnCombined(pos.InactiveSideOccupiedSqsBb(), enPassanteBB))
+	addPawnMovesToArray(mvsList, int(attackLeftShift), directionOfMovement, pawnAttackBb, promotionRank)
+
+	pawnAttackBb = getShiftedBb(&pawnPosBb, attackRightShift).
+		RemoveOverlappingBits(aFileBb).
+		BitwiseAnd(bitboard.ReturnCombined(pos.InactiveSideOccupiedSqsBb(), enPassanteBB))
+	addPawnMovesToArray(mvsList, int(attackRightShift), directionOfMovement, pawnAttackBb, promotionRank)
+
+	// pushes are only of interest when they promote
+	pawnPushBb := getShiftedBb(&pawnPosBb, 8)
+	pawnPushBb.RemoveOverlappingBits(pos.AllOccupiedSqsBb())
+	pawnPushBb.BitwiseAnd(promotionRank)
+	addPawnMovesToArray(mvsList, 8, directionOfMovement, pawnPushBb, promotionRank)
+}
+
 func addPawnMovesToArray(movesList *moves.Moves, shift int, shiftDirection int, pawnPushBb *bitboard.Bitboard, promoRank *bitboard.Bitboard) {
 	shift = shift * shiftDirection
 	for pawnPushBb.Value() != 0 {
@@ -230,6 +311,21 @@ func getKnightMovesBb(index int, occSqsBb uint64, ht *hashtables.HashTables) *bi
 	return bb
 }
 
+func getBishopMovesBb(index int, occSqsBb uint64, ht *hashtables.HashTables) *bitboard.Bitboard {
+	return bitboard.NewBitboard(ht.BishopAttacks(index, occSqsBb))
+}
+
+func getRookMovesBb(index int, occSqsBb uint64, ht *hashtables.HashTables) *bitboard.Bitboard {
+	return bitboard.NewBitboard(ht.RookAttacks(index, occSqsBb))
+}
+
+func getQueenMovesBb(index int, occSqsBb uint64, ht *hashtables.HashTables) *bitboard.Bitboard {
+	return bitboard.NewBitboard(ht.QueenAttacks(index, occSqsBb))
+}
+
+// The ray based sliding move generation below scans one direction at a time.
+// Magic bitboards replaced it, it is kept as the reference they are tested against.
+
 func generateSlidingMovesBb(index int, occSqsBb uint64, ht *hashtables.HashTables) *bitboard.Bitboard {
 	slidingMvs := generateValidDiagonalSlidingMovesBb(index, occSqsBb, ht)
 	slidingMvs.Combine(generateValidStraightSlidingMovesBb(index, occSqsBb, ht))
@@ -160,7 +160,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"Legal queen moves from a1 blocked horizontally": {
-			pos: "7k/8/8/8/8/8/8/QK6 w KQkq - 0 1",
+			pos: "8/7k/8/8/8/8/8/QK6 w - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateQueenMoves(pos, mvs, ht)
@@ -197,7 +197,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"white king middle of board unblocked": {
-			pos: "7k/8/8/8/3K4/8/8/3B4 w KQkq - 0 1",
+			pos: "7k/8/8/8/3K4/8/8/3B4 w - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -233,7 +233,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"black king middle of board completely blocked": {
-			pos: "8/8/8/pppppppp/rrrkrrrr/rrrrrrrr/8/3B4 b - - 0 1",
+			pos: "7K/8/8/pppppppp/rrrkrrrr/rrrrrrrr/8/3B4 b - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -257,7 +257,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"black king middle of board surrounded by opposition": {
-			pos: "8/8/8/PPPPPPPP/RRRkRRRR/RRRRRRRR/8/3B4 b - - 0 1",
+			pos: "K7/8/8/PPPPPPPP/RRRkRRRR/RRRRRRRR/8/3B4 b - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -269,7 +269,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"white castling king-side": {
-			pos: "7k/8/8/8/8/8/PPPPPPPP/3QK3 w K - 0 1",
+			pos: "7k/8/8/8/8/8/PPPPPPPP/3QK2R w K - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -281,7 +281,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"black castling king-side": {
-			pos: "3rk3/pppppppp/8/8/8/8/PPPPPPPP/3QK3 b kq - 0 1",
+			pos: "3rk2r/pppppppp/8/8/8/8/PPPPPPPP/3QK3 b k - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -293,7 +293,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"white castling queen-side": {
-			pos: "7k/8/8/8/8/8/PPPPPPPP/R3KQ2 w KQ - 0 1",
+			pos: "7k/8/8/8/8/8/PPPPPPPP/R3KQ2 w Q - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -306,7 +306,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"black castling queen-side": {
-			pos: "r3kr2/pppppppp/8/8/8/8/PPPPPPPP/3QK3 b kq - 0 1",
+			pos: "r3kr2/pppppppp/8/8/8/8/PPPPPPPP/3QK3 b q - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -330,7 +330,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"black w/o castling permission": {
-			pos: "r3k2r/ppppppppp/8/8/8/8/PPPPPPPP/3QK3 b - - 0 1",
+			pos: "r3k2r/pppppppp/8/8/8/8/PPPPPPPP/3QK3 b - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -354,7 +354,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"black castling both-sides": {
-			pos: "r3k2r/ppppppppp/8/8/8/8/PPPPPPPP/3QK3 b KQkq - 0 1",
+			pos: "r3k2r/pppppppp/8/8/8/8/PPPPPPPP/3QK3 b kq - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -378,7 +378,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"black has castling rights but blocked by own pieces both sides": {
-			pos: "r2qkq1r/ppppppppp/8/8/8/8/PPPPPPPP/3QK3 b KQkq - 0 1",
+			pos: "r2qkq1r/pppppppp/8/8/8/8/PPPPPPPP/3QK3 b kq - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -402,7 +402,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"black has castling rights but blocked by opposition pieces both sides": {
-			pos: "r2QkQ1r/ppppppppp/8/8/8/8/PPPPPPPP/3QK3 b KQkq - 0 1",
+			pos: "r2QkQ1r/pppppppp/8/8/8/8/PPPPPPPP/3QK3 b kq - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -414,7 +414,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"white has castling rights but blocked by opposition pieces on both sides with a space to move": {
-			pos: "7k/8/8/8/8/8/PPPPPPPP/R1q1K1qR w KQkq - 0 1",
+			pos: "7k/8/8/8/8/8/PPPPPPPP/R1q1K1qR w KQ - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -426,7 +426,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"black has castling rights but blocked by opposition pieces on both sides with a space to move": {
-			pos: "r1Q1k1Qr/ppppppppp/8/8/8/8/PPPPPPPP/3QK3 b kq - 0 1",
+			pos: "r1Q1k1Qr/pppppppp/8/8/8/8/PPPPPPPP/3QK3 b kq - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -438,7 +438,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"king moves castling and an attack an seventh rank": {
-			pos: "r3k2r/5N2/8/8/8/8/8/7K b KQkq - 0 1",
+			pos: "r3k2r/5N2/8/8/8/8/8/6K1 b kq - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKingMoves(pos, mvs, ht)
@@ -473,7 +473,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"bishop  moves wide open spaces": {
-			pos: "7k/8/8/8/8/8/8/6KB w KQkq - 0 1",
+			pos: "7k/8/8/8/8/8/8/6KB w - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateBishopMoves(pos, mvs, ht)
@@ -485,7 +485,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"legal white bishop moves blocked on right array": {
-			pos: "7k/8/8/8/8/5r2/8/3B3K w KQkq - 0 1",
+			pos: "7k/8/8/8/8/5r2/8/3B3K w - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateBishopMoves(pos, mvs, ht)
@@ -520,7 +520,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"Legal rook moves from a1 blocked horizontally": {
-			pos: "7k/8/8/8/8/8/8/RK6 w KQkq - 0 1",
+			pos: "7k/8/8/8/8/8/8/RK6 w - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateRookMoves(pos, mvs, ht)
@@ -532,7 +532,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"legal rook moves from d4 unblocked": {
-			pos: "7k/8/8/8/3R4/8/8/7K w KQkq - 0 1",
+			pos: "7k/8/8/8/3R4/8/8/7K w - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateRookMoves(pos, mvs, ht)
@@ -556,7 +556,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"Legal knight moves from a1 blocked at c2 as white": {
-			pos: "7k/8/8/8/8/8/2B5/NK6 w KQkq - 0 1",
+			pos: "7k/8/8/8/8/8/2B5/NK6 w - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKnightMoves(pos, mvs, ht)
@@ -568,7 +568,7 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 			},
 		},
 		"Legal knight moves from a1 blocked at c2 as black": {
-			pos: "7k/8/8/8/8/1B6/2b5/nK6 b KQkq - 0 1",
+			pos: "7k/8/8/8/8/1B6/2b5/n6K b - - 0 1",
 			generateMoves: func(pos *position.Position) *moves.Moves {
 				mvs := moves.NewMovesList()
 				GenerateKnightMoves(pos, mvs, ht)
@@ -586,3 +586,32 @@ func TestGeneratePsuedoLegalMoves(t *testing.T) {
 		test.assertion(legalMoves, tName)
 	}
 }
+
+func TestGenerateCaptures(t *testing.T) {
+	tests := map[string]struct {
+		pos      string
+		expected int
+	}{
+		"no captures from the starting position": {pos: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", expected: 0},
+		"kiwipete":                               {pos: "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", expected: 8},
+		"en passante":                            {pos: "rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 1", expected: 1},
+		"promotions and captures":                {pos: "n1n5/PPPk4/8/8/8/8/4Kppp/5N1N b - - 0 1", expected: 15},
+		"king captures as black":                 {pos: "8/8/8/8/8/8/3Pk3/K7 b - - 0 1", expected: 1},
+		"pieces capture as black":                {pos: "4k3/8/8/3q4/8/1N3B2/K7/N5r1 b - - 0 1", expected: 3},
+		"blocked sliding piece":                  {pos: "4k3/8/8/8/8/8/P7/R3K3 w - - 0 1", expected: 0},
+		"promotion push is a check":              {pos: "4k3/P7/8/8/8/8/8/4K3 w - - 0 1", expected: 4},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.pos)
+		captures := GenerateCaptures(pos)
+		assert.Equal(t, test.expected, captures.Length(), tName)
+		// every capture is one of the pseudo legal moves that takes a piece or promotes
+		allMoves := GenerateMoves(pos)
+		for _, mv := range captures.GetMovesList() {
+			_, ok := allMoves.FindMove(mv.Origin(), mv.Destination(), mv.PromotionPiece())
+			assert.True(t, ok, tName)
+			isCapture := pos.InactiveSideOccupiedSqsBb().BitIsSet(mv.Destination()) || mv.Destination() == pos.EnPassante()
+			assert.True(t, isCapture || mv.PromotionPiece() != 0, tName)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package generate
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -40,3 +41,36 @@ func TestGenerateValidDiagonalSlidingMovesBb(t *testing.T) {
 	expectedValidMvsBb = bitboard.NewBitboard(uint64(0x402000204000000))
 	assert.Equal(t, expectedValidMvsBb.Value(), validMvsBb.Value())
 }
+
+func TestMagicMatchesRayAttacks(t *testing.T) {
+	ht := hashtables.Lookup
+	rng := rand.New(rand.NewSource(1))
+	for sq := 0; sq < 64; sq++ {
+		for i := 0; i < 1000; i++ {
+			// sparse and dense boards both occur in games
+			occupied := rng.Uint64() & rng.Uint64()
+			if i%2 == 0 {
+				occupied &= rng.Uint64()
+			}
+			assert.Equal(t, generateValidDiagonalSlidingMovesBb(sq, occupied, ht).Value(), ht.BishopAttacks(sq, occupied), "bishop on %d", sq)
+			assert.Equal(t, generateValidStraightSlidingMovesBb(sq, occupied, ht).Value(), ht.RookAttacks(sq, occupied), "rook on %d", sq)
+			assert.Equal(t, generateSlidingMovesBb(sq, occupied, ht).Value(), ht.QueenAttacks(sq, occupied), "queen on %d", sq)
+		}
+	}
+}
+
+func BenchmarkRayAttacks(b *testing.B) {
+	ht := hashtables.Lookup
+	occupied := uint64(0x91ff00281000ff91)
+	for i := 0; i < b.N; i++ {
+		generateSlidingMovesBb(i&63, occupied, ht)
+	}
+}
+
+func BenchmarkMagicAttacks(b *testing.B) {
+	ht := hashtables.Lookup
+	occupied := uint64(0x91ff00281000ff91)
+	for i := 0; i < b.N; i++ {
+		ht.QueenAttacks(i&63, occupied)
+	}
+}
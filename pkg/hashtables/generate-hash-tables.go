@@ -40,11 +40,18 @@ type HashTables struct {
 	LegalKingMovesNoCastlingBbHash        [64]uint64
 	CastlingBits                          [2]uint64
 	LegalPawnMovesBbHash                  [2][64]uint64
+	PawnAttackBbHash                      [2][64]uint64
 	WhiteKingSideCastlingBitsMustBeClear  uint64
 	BlacklKingSideCastlingBitsMustBeClear uint64
 	WhiteQueenSideCastlingBitsMustBeClear uint64
 	BlackQueenSideCastlingBitsMustBeClear uint64
 	LookupCastlingSlidingSqByDest         map[uint64]uint64
+	RookMagics                            [64]Magic
+	BishopMagics                          [64]Magic
+	FileMaskBb                            [8]uint64
+	AdjacentFilesBb                       [8]uint64
+	ForwardRanksBb                        [2][64]uint64
+	PassedPawnMaskBb                      [2][64]uint64
 }
 
 func CalculateAllLookupBbs() *HashTables {
@@ -89,6 +96,8 @@ func CalculateAllLookupBbs() *HashTables {
 	generateSingleBitLookup(hashTables)
 	generateArrayBitboardLookup(hashTables)
 	generateEnPassantBitboardLookup(hashTables)
+	generateMagicBitboards(hashTables)
+	generatePawnStructureMasks(hashTables)
 
 	hashTables.CastlingBits[0] = 0
 	hashTables.CastlingBits[0] |= hashTables.SingleIndexBbHash[62] | hashTables.SingleIndexBbHash[58]
@@ -281,6 +290,29 @@ func generateArrayBitboardLookup(ht *HashTables) {
 			ht.LegalPawnMovesBbHash[1][index] &= ^ht.AfileBb
 			ht.LegalPawnMovesBbHash[0][index] &= ^ht.AfileBb
 		}
+		// squares attacked diagonally by a pawn of either side on index
+		ht.PawnAttackBbHash[1][index] = 0
+		ht.PawnAttackBbHash[0][index] = 0
+		if index <= 56 {
+			ht.PawnAttackBbHash[1][index] |= ht.SingleIndexBbHash[index+7]
+		}
+		if index <= 54 {
+			ht.PawnAttackBbHash[1][index] |= ht.SingleIndexBbHash[index+9]
+		}
+		if index >= 7 {
+			ht.PawnAttackBbHash[0][index] |= ht.SingleIndexBbHash[index-7]
+		}
+		if index >= 9 {
+			ht.PawnAttackBbHash[0][index] |= ht.SingleIndexBbHash[index-9]
+		}
+		if ht.SingleIndexBbHash[index]&ht.AfileBb != 0 {
+			ht.PawnAttackBbHash[1][index] &= ^ht.HfileBb
+			ht.PawnAttackBbHash[0][index] &= ^ht.HfileBb
+		}
+		if ht.SingleIndexBbHash[index]&ht.HfileBb != 0 {
+			ht.PawnAttackBbHash[1][index] &= ^ht.AfileBb
+			ht.PawnAttackBbHash[0][index] &= ^ht.AfileBb
+		}
 	}
 }
 
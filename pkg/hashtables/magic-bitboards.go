@@ -0,0 +1,178 @@
+package hashtables
+
+import "math/bits"
+
+// Magic looks up the attacks of a sliding piece on one square. The occupied squares that
+// can block the piece, Mask, are multiplied by the magic number, which gathers them in the
+// top bits of the product, and those bits index the attack sets of every blocker configuration.
+type Magic struct {
+	Mask    uint64
+	Magic   uint64
+	Shift   uint
+	Attacks []uint64
+}
+
+// index returns the position of the attacks for the occupied squares in the table
+func (m *Magic) index(occupied uint64) uint64 {
+	return ((occupied & m.Mask) * m.Magic) >> m.Shift
+}
+
+// RookAttacks returns the squares a rook on sq attacks, up to and including the first blocker in each direction
+func (ht *HashTables) RookAttacks(sq int, occupied uint64) uint64 {
+	m := &ht.RookMagics[sq]
+	return m.Attacks[m.index(occupied)]
+}
+
+// BishopAttacks returns the squares a bishop on sq attacks, up to and including the first blocker in each direction
+func (ht *HashTables) BishopAttacks(sq int, occupied uint64) uint64 {
+	m := &ht.BishopMagics[sq]
+	return m.Attacks[m.index(occupied)]
+}
+
+// QueenAttacks returns the squares a queen on sq attacks
+func (ht *HashTables) QueenAttacks(sq int, occupied uint64) uint64 {
+	return ht.RookAttacks(sq, occupied) | ht.BishopAttacks(sq, occupied)
+}
+
+// directions as rank and file steps, north lowers the rank index
+var (
+	rookDirections   = [4][2]int{{-1, 0}, {1, 0}, {0, 1}, {0, -1}}
+	bishopDirections = [4][2]int{{-1, 1}, {-1, -1}, {1, 1}, {1, -1}}
+)
+
+// magicSeed starts the search for magic numbers that are not known yet
+const magicSeed = 0x2545F4914F6CDD1D
+
+// rookMagicNumbers and bishopMagicNumbers were found by findMagic starting from magicSeed.
+// Searching takes a few hundred milliseconds, mostly on the rook corners,
+// so only the attack tables are filled in at start up.
+var rookMagicNumbers = [64]uint64{
+	0x8000908064C000, 0x40200040001000, 0x180100080A0010A, 0x8880041000800800,
+	0x1200100201200804, 0x200020004011008, 0x2180010000800600, 0x200005088210204,
+	0x800080204001, 0x1000804000802001, 0x8240801000200080, 0x8611001004200900,
+	0x8180800C001800, 0x100800200800400, 0xA02000102000408, 0x8020802300104280,
+	0x80004000402000, 0xE010104000402000, 0x800808010002000, 0xA280210008100100,
+	0x1818014000800, 0xA002010100080400, 0x8040088020130, 0x1020004048845,
+	0x81826280004004, 0x2020810900284000, 0x200100080802000, 0x200080080100080,
+	0x8083080100100500, 0x4406000901000400, 0x5020080800100, 0x90204200008114,
+	0x10400094800420, 0x900804000802002, 0x201001841002000, 0x4100080080801000,
+	0x4540040080800800, 0x800400800200, 0x9281800100808200, 0x8004048102000854,
+	0x4420802040008006, 0x880500020004002, 0x801200241050010, 0x8400080010008080,
+	0x8000500090010, 0x82009084020008, 0x4012000108020004, 0x9000104D08860004,
+	0x2004204114800100, 0x148802112400300, 0x202842000100880, 0x1B080080900080,
+	0x1A002008100600, 0x4008004020080, 0x5181000600040300, 0x44401128A00,
+	0x8044110480002441, 0x1023012082044112, 0x804080200A0012, 0x420310A004A42,
+	0x23001004020801, 0x882001008040102, 0x230088118020C, 0x19025040042,
+}
+
+var bishopMagicNumbers = [64]uint64{
+	0x1010220204082A00, 0x80E0020202002804, 0x2008480104200020, 0x220920280002D,
+	0x32040421000B0284, 0x1002080404000400, 0x4160892080040, 0x2203024206204201,
+	0x2404264010200, 0x1120908408428124, 0xB100424403002280, 0x240008060440C288,
+	0x2040040420490400, 0x100620210040022, 0x400084104202028, 0x10050080908820,
+	0xC90A04490824802, 0x200A008210130, 0xC08001000204010, 0x8000186014480,
+	0x601044820080021, 0x2000101013100, 0x1400A08108080204, 0x250401104485410,
+	0x4820240810142843, 0x9142A20182200, 0x848140048440020, 0x2020120000400440,
+	0x108840200802003, 0x9070082009492, 0x20C0C0038424245, 0xCA44005808210410,
+	0x8011212000500404, 0x2028840510101008, 0x4042A00041400, 0x624020080980080,
+	0x1820410040840040, 0x2201004202050100, 0x402A088A24040224, 0x242061040002400,
+	0x90020202400821A0, 0xC9009004E01002, 0x58C2060202023100, 0x12214040800,
+	0x210846810100200, 0x4208081010200, 0x1A4108404442100, 0x8054082C80280106,
+	0x4144904104208, 0x324C0A11104000, 0x1000020231040100, 0x2080001042020004,
+	0x544021020288104, 0x1103501408083020, 0x4010451004960002, 0x3010091C44902C,
+	0x102402884202000, 0x480804C00841086, 0x4602C8602210400, 0x4000420200,
+	0x40000020442C18, 0x4483804089094100, 0x80000B0248020400, 0x45010808008680,
+}
+
+func generateMagicBitboards(ht *HashTables) {
+	state := uint64(magicSeed)
+	for sq := 0; sq < 64; sq++ {
+		ht.RookMagics[sq] = findMagic(sq, rookDirections, rookMagicNumbers[sq], &state)
+		ht.BishopMagics[sq] = findMagic(sq, bishopDirections, bishopMagicNumbers[sq], &state)
+	}
+}
+
+// findMagic fills the attack table of sq for the first number that maps every blocker configuration
+// to an entry that is either unused or holds the same attacks. It tries known first, then sparse random numbers.
+func findMagic(sq int, directions [4][2]int, known uint64, state *uint64) Magic {
+	mask := slidingMask(sq, directions)
+	bitCount := bits.OnesCount64(mask)
+	size := 1 << uint(bitCount)
+	occupancies := make([]uint64, size)
+	attacks := make([]uint64, size)
+	// enumerate every subset of the mask with the carry-rippler trick
+	occupied := uint64(0)
+	for i := 0; i < size; i++ {
+		occupancies[i] = occupied
+		attacks[i] = slidingAttacks(sq, occupied, directions)
+		occupied = (occupied - mask) & mask
+	}
+	m := Magic{Mask: mask, Shift: uint(64 - bitCount), Attacks: make([]uint64, size)}
+	// usedInAttempt tells which entries the current attempt filled, without clearing them between attempts
+	usedInAttempt := make([]int, size)
+	for attempt := 1; ; attempt++ {
+		m.Magic = known
+		if attempt > 1 {
+			m.Magic = random(state) & random(state) & random(state)
+		}
+		// a good magic moves enough mask bits into the top byte
+		if bits.OnesCount64((mask*m.Magic)&0xFF00000000000000) < 6 {
+			continue
+		}
+		collision := false
+		for i := 0; i < size && !collision; i++ {
+			index := m.index(occupancies[i])
+			if usedInAttempt[index] == attempt && m.Attacks[index] != attacks[i] {
+				collision = true
+			}
+			usedInAttempt[index] = attempt
+			m.Attacks[index] = attacks[i]
+		}
+		if !collision {
+			return m
+		}
+	}
+}
+
+// slidingMask returns the squares whose occupation can block a piece on sq. The last square
+// of each direction is left out, since nothing lies behind it to be blocked.
+func slidingMask(sq int, directions [4][2]int) uint64 {
+	mask := uint64(0)
+	for _, direction := range directions {
+		rank, file := sq/8+direction[0], sq%8+direction[1]
+		for onBoard(rank+direction[0], file+direction[1]) {
+			mask |= uint64(1) << uint(rank*8+file)
+			rank, file = rank+direction[0], file+direction[1]
+		}
+	}
+	return mask
+}
+
+// slidingAttacks walks from sq in every direction until it leaves the board or hits a piece
+func slidingAttacks(sq int, occupied uint64, directions [4][2]int) uint64 {
+	attacks := uint64(0)
+	for _, direction := range directions {
+		rank, file := sq/8+direction[0], sq%8+direction[1]
+		for onBoard(rank, file) {
+			bit := uint64(1) << uint(rank*8+file)
+			attacks |= bit
+			if occupied&bit != 0 {
+				break
+			}
+			rank, file = rank+direction[0], file+direction[1]
+		}
+	}
+	return attacks
+}
+
+func onBoard(rank int, file int) bool {
+	return rank >= 0 && rank < 8 && file >= 0 && file < 8
+}
+
+// random is the xorshift64* pseudo random number generator
+func random(state *uint64) uint64 {
+	*state ^= *state >> 12
+	*state ^= *state << 25
+	*state ^= *state >> 27
+	return *state * 2685821657736338717
+}
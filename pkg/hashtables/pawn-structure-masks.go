@@ -0,0 +1,33 @@
+package hashtables
+
+// generatePawnStructureMasks fills the masks that pawn structure evaluation looks up:
+// FileMaskBb and AdjacentFilesBb by file, ForwardRanksBb with the ranks ahead of a square
+// from the point of view of each side, and PassedPawnMaskBb with the squares ahead of a pawn,
+// on its own and the adjacent files, that no opposing pawn may occupy for it to be passed.
+func generatePawnStructureMasks(ht *HashTables) {
+	files := [8]uint64{ht.AfileBb, ht.BfileBb, ht.CfileBb, ht.DfileBb, ht.EfileBb, ht.FfileBb, ht.GfileBb, ht.HfileBb}
+	for file := 0; file < 8; file++ {
+		ht.FileMaskBb[file] = files[file]
+		if file > 0 {
+			ht.AdjacentFilesBb[file] |= files[file-1]
+		}
+		if file < 7 {
+			ht.AdjacentFilesBb[file] |= files[file+1]
+		}
+	}
+	for sq := 0; sq < 64; sq++ {
+		rank, file := sq/8, sq%8
+		for r := 0; r < 8; r++ {
+			rankBb := uint64(0xFF) << uint(8*r)
+			if r < rank {
+				ht.ForwardRanksBb[0][sq] |= rankBb
+			}
+			if r > rank {
+				ht.ForwardRanksBb[1][sq] |= rankBb
+			}
+		}
+		for side := 0; side < 2; side++ {
+			ht.PassedPawnMaskBb[side][sq] = ht.ForwardRanksBb[side][sq] & (ht.FileMaskBb[file] | ht.AdjacentFilesBb[file])
+		}
+	}
+}
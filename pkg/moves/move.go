@@ -47,13 +47,18 @@ func (m *Move) Print() {
 	fmt.Println(ConvertIndexToAlgebraic(m.origin) + ConvertIndexToAlgebraic(m.destination))
 }
 
+// String returns the move in UCI long algebraic notation, e.g. e2e4 or e7e8q
 func (m *Move) String() string {
 	mvString := ConvertIndexToAlgebraic(m.origin) + ConvertIndexToAlgebraic(m.destination)
-	if (m.promotion > 0) && (m.destination < 8) {
-		mvString += "Q"
-	}
-	if (m.promotion > 0) && (m.destination > 55) {
+	switch m.promotion {
+	case 2:
 		mvString += "q"
+	case 3:
+		mvString += "b"
+	case 4:
+		mvString += "n"
+	case 5:
+		mvString += "r"
 	}
 	return mvString
 }
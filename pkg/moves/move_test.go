@@ -30,3 +30,9 @@ func TestConvertAlgebriacToIndex(t *testing.T) {
 	index, _ = ConvertAlgebriacToIndex("h8")
 	assert.Equal(t, 7, index)
 }
+
+func TestMoveString(t *testing.T) {
+	assert.Equal(t, "e2e4", NewMove([]int{52, 36}).String())
+	assert.Equal(t, "e7e8q", NewPromoMove([]int{12, 4, 2}).String())
+	assert.Equal(t, "a2a1n", NewPromoMove([]int{48, 56, 4}).String())
+}
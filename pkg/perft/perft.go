@@ -0,0 +1,82 @@
+// Package perft counts the leaf nodes of the legal move tree of a position.
+// Comparing the counts against published results is the standard way
+// to verify move generation, including castling, en passante and promotions.
+package perft
+
+import (
+	"github.com/tonyOreglia/glee/pkg/engine"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// MoveCount is the number of leaf nodes below a single legal move of the root position
+type MoveCount struct {
+	Move  moves.Move
+	Nodes int
+}
+
+// Perft counts the positions reached after exactly depth legal moves from pos.
+// pos is left unchanged.
+func Perft(pos *position.Position, depth int) int {
+	p := pos.Copy()
+	return perft(&p, depth)
+}
+
+// Divide breaks the perft count down by legal move of pos, in move generation order.
+// pos is left unchanged.
+func Divide(pos *position.Position, depth int) []MoveCount {
+	counts := []MoveCount{}
+	if depth < 1 {
+		return counts
+	}
+	p := pos.Copy()
+	for _, move := range generate.GenerateMoves(p).GetMovesList() {
+		if !engine.MakeValidMove(move, &p) {
+			continue
+		}
+		counts = append(counts, MoveCount{Move: move, Nodes: perft(&p, depth-1)})
+		p = p.UnMakeMove()
+	}
+	return counts
+}
+
+func perft(pos **position.Position, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+	nodes := 0
+	for _, move := range generate.GenerateMoves(*pos).GetMovesList() {
+		if !engine.MakeValidMove(move, pos) {
+			continue
+		}
+		nodes += perft(pos, depth-1)
+		*pos = (*pos).UnMakeMove()
+	}
+	return nodes
+}
+
+// PerftLegal counts like Perft with the legal move generator, which needs no move to be made
+// to find out whether it is legal. The two counts agreeing cross-checks both generators.
+// pos is left unchanged.
+func PerftLegal(pos *position.Position, depth int) int {
+	return perftLegal(pos.Copy(), depth)
+}
+
+func perftLegal(pos *position.Position, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+	mvs := generate.GenerateLegalMoves(pos).GetMovesList()
+	// every legal move leads to exactly one leaf
+	if depth == 1 {
+		return len(mvs)
+	}
+	nodes := 0
+	for _, move := range mvs {
+		pos.Move(move)
+		nodes += perftLegal(pos, depth-1)
+		pos.UnMakeMove()
+	}
+	return nodes
+}
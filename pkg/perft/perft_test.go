@@ -0,0 +1,135 @@
+package perft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// node counts from https://www.chessprogramming.org/Perft_Results
+func TestPerft(t *testing.T) {
+	tests := map[string]struct {
+		fen   string
+		nodes []int
+	}{
+		"initial position": {
+			fen:   "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			nodes: []int{20, 400, 8902, 197281},
+		},
+		"kiwipete": {
+			fen:   "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+			nodes: []int{48, 2039, 97862},
+		},
+		"position 3": {
+			fen:   "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+			nodes: []int{14, 191, 2812, 43238},
+		},
+		"position 4": {
+			fen:   "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+			nodes: []int{6, 264, 9467, 422333},
+		},
+		"position 5": {
+			fen:   "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+			nodes: []int{44, 1486, 62379},
+		},
+		"position 6": {
+			fen:   "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+			nodes: []int{46, 2079, 89890},
+		},
+	}
+	for tName, test := range tests {
+		pos, err := position.NewPositionFen(test.fen)
+		assert.Nil(t, err, tName)
+		assert.Equal(t, 1, Perft(pos, 0), tName)
+		for i, nodes := range test.nodes {
+			assert.Equal(t, nodes, Perft(pos, i+1), "%s at depth %d", tName, i+1)
+			assert.Equal(t, nodes, PerftLegal(pos, i+1), "%s at depth %d with legal moves", tName, i+1)
+		}
+		assert.Equal(t, test.fen, pos.GetFenString(), tName)
+	}
+}
+
+// positions along lines of the standard positions that once exposed move generation bugs
+func TestPerftPositions(t *testing.T) {
+	tests := []struct {
+		fen   string
+		depth int
+		nodes int
+		name  string
+	}{
+		{fen: "n1n5/PPPk4/8/8/8/8/4Kppp/5N1N b - - 0 1", depth: 1, nodes: 24, name: ""},
+		{fen: "n1n5/PPPk4/8/8/8/8/4Kppp/5N1N b - - 0 1", depth: 2, nodes: 496, name: ""},
+		{fen: "r3k2r/p1ppqNb1/bn2pnp1/3P4/1p2P3/2N2Q1p/PPPBBPPP/R3K2R b KQkq - 0 1", depth: 4, nodes: 4164923, name: "test position 001"},
+		{fen: "r3k2r/p1ppqNb1/1n2pnp1/3P4/1p2P3/2N2Q1p/PPPBbPPP/R3K2R w KQkq - 0 1", depth: 1, nodes: 41, name: "test position 001 --> a6e2"},
+		{fen: "1r2k2r/p1ppqNb1/bn2pnp1/3P4/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQ - 1 1", depth: 1, nodes: 48, name: "test position 001 --> a8b8"},
+		{fen: "2r1k2r/p1ppqNb1/bn2pnp1/3P4/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQ - 1 1", depth: 1, nodes: 48, name: "test position 001 --> a8c8"},
+		{fen: "r3k2r/p1ppqNb1/1n2pnp1/1b1P4/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 1 1", depth: 2, nodes: 2084, name: "test position 001 --> a6b5"},
+		{fen: "r3k2r/p1ppqNb1/1n2pnp1/1b1P4/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 1 1", depth: 3, nodes: 97761, name: "test position 001 --> a6b5"},
+		{fen: "r3k2r/p1ppqNb1/1n2pnp1/1b1P4/Pp2P3/2N2Q1p/1PPBBPPP/R3K2R b KQkq a3 0 1", depth: 2, nodes: 2275, name: "test position 001 --> a6b5, a2a4"},
+		{fen: "r3k2r/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", depth: 2, nodes: 2120, name: "test position 001 --> b4c3"},
+		{fen: "r3k2r/p1ppqNb1/1n2pnp1/1b1P4/4P3/2p2Q1p/PPPBBPPP/1R2K2R w kq - 2 1", depth: 2, nodes: 2073, name: "test position 002 (001 --> b4c3 --> a1b1 --> a6b5)"},
+		{fen: "r3k2r/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/1R2K2R b kq - 1 1", depth: 2, nodes: 2039, name: "test position 001 --> b4c3 --> a1b1"},
+		{fen: "r3k2r/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/1R2K2R b kq - 1 1", depth: 3, nodes: 90010, name: "test position 001 --> b4c3 --> a1b1"},
+		{fen: "r3k2r/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", depth: 3, nodes: 97799, name: "test position 001 --> b4c3"},
+		{fen: "r3k2r/p1ppqNb1/bn3np1/3p4/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", depth: 3, nodes: 99231, name: "test position 001 --> e6d5"},
+		{fen: "r3k2r/p1ppqNb1/bn3np1/3P4/1p6/2N2Q1p/PPPBBPPP/R3K2R b KQkq - 0 1", depth: 2, nodes: 1951, name: "test position 001 --> e6d5 --> e4d5"},
+		{fen: "k7/8/6p1/8/8/7P/8/K7 b - - 0 1", depth: 5, nodes: 4354, name: "test position 002"},
+		{fen: "n1n5/PPPk4/8/8/8/8/4Kppp/5N1N b - - 0 1", depth: 4, nodes: 182838, name: "test position 003"},
+		{fen: "R6r/8/8/2K5/5k2/8/8/r6R b - - 0 1", depth: 4, nodes: 771368, name: "test position 004"},
+	}
+	for _, test := range tests {
+		pos, err := position.NewPositionFen(test.fen)
+		assert.Nil(t, err, test.fen)
+		assert.Equal(t, test.nodes, Perft(pos, test.depth), "%s %s", test.name, test.fen)
+		assert.Equal(t, test.nodes, PerftLegal(pos, test.depth), "%s %s with legal moves", test.name, test.fen)
+	}
+}
+
+// the legal move generator is fast enough to go one ply deeper
+func TestPerftLegalDeeper(t *testing.T) {
+	tests := map[string]struct {
+		fen   string
+		depth int
+		nodes int
+	}{
+		"kiwipete":   {fen: "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", depth: 4, nodes: 4085603},
+		"position 3": {fen: "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", depth: 5, nodes: 674624},
+		"position 5": {fen: "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", depth: 4, nodes: 2103487},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.fen)
+		assert.Equal(t, test.nodes, PerftLegal(pos, test.depth), tName)
+	}
+}
+
+func BenchmarkPerft(b *testing.B) {
+	pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	for i := 0; i < b.N; i++ {
+		Perft(pos, 2)
+	}
+}
+
+func BenchmarkPerftLegal(b *testing.B) {
+	pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	for i := 0; i < b.N; i++ {
+		PerftLegal(pos, 2)
+	}
+}
+
+func TestDivide(t *testing.T) {
+	pos, _ := position.NewPositionFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	counts := Divide(pos, 2)
+	assert.Equal(t, 48, len(counts))
+	total := 0
+	byMove := map[string]int{}
+	for _, count := range counts {
+		total += count.Nodes
+		byMove[count.Move.String()] = count.Nodes
+	}
+	assert.Equal(t, 2039, total)
+	assert.Equal(t, 43, byMove["e1g1"])
+	assert.Equal(t, 43, byMove["e1c1"])
+	assert.Equal(t, 46, byMove["d5e6"])
+	assert.Equal(t, 0, len(Divide(pos, 0)))
+}
@@ -5,7 +5,6 @@ package position
 import (
 	"errors"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
@@ -23,6 +22,8 @@ const White = 0
 // Black is the index of Black's position bitboards in instance of Position Struct
 const Black = 1
 
+// Squares are numbered rank by rank from a8 = 0 to h1 = 63, so white moves towards the lower
+// squares. The castling rights bits are the squares the king castles to.
 const WhiteKingSideCastlingRightsBit = 62
 const WhiteQueenSideCastlingRightsBit = 58
 const BlackKingSideCastlingRightsBit = 6
@@ -38,49 +39,84 @@ const Pawns = 6
 
 // Position struct represents a static chess position
 type Position struct {
-	bitboards      [2][]bitboard.Bitboard
-	castlingRights []bitboard.Bitboard
+	bitboards      [2][7]bitboard.Bitboard
+	castlingRights [2]bitboard.Bitboard
 	activeSide     int
 	enPassanteSq   int
 	moveCt         int
 	halfMoveCt     int
-	previousPos    *Position
+	hash           uint64
+	pawnHash       uint64
+	// history holds one undo record per move made, the last move is taken back first
+	history []undo
+}
+
+// undo holds what taking a move back cannot work out from the position after the move
+type undo struct {
+	origin, terminus uint8
+	movingPiece      uint8
+	// capturedPiece is OccupiedSqs unless the move captured on capturedSq
+	capturedPiece  uint8
+	capturedSq     uint8
+	promotionPiece uint8
+	nullMove       bool
+	castlingRights [2]bitboard.Bitboard
+	enPassanteSq   int
+	halfMoveCt     int
+	hash           uint64
+	pawnHash       uint64
 }
 
+// historyCapacity is the number of moves a position makes room for up front,
+// so that making moves during a search does not allocate
+const historyCapacity = 256
+
 func StartingPosition() *Position {
 	p, _ := NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
 	return p
 }
 
-// NewPositionFen constructs Position struct instance from Forth-Edwards Notation string
+// NewPositionFen constructs Position struct instance from Forth-Edwards Notation string.
+// Malformed or impossible positions are rejected with an error describing the problem.
 func NewPositionFen(fen string) (*Position, error) {
 	p := new(Position)
-	p.bitboards[0] = make([]bitboard.Bitboard, 7)
-	p.bitboards[1] = make([]bitboard.Bitboard, 7)
-	p.castlingRights = make([]bitboard.Bitboard, 2)
-	Position, activeSide, castlingRights, enPassanteSq, moveCount, halfMoveCount := getFenStringTokens(fen)
-	p.setBitboardsFromFen(Position, activeSide)
+	Position, activeSide, castlingRights, enPassanteSq, moveCount, halfMoveCount, err := getFenStringTokens(fen)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.setBitboardsFromFen(Position); err != nil {
+		return nil, err
+	}
 	p.setActiveSide(activeSide)
-	p.setCastlingRightsFromFen(castlingRights)
+	if err := p.setCastlingRightsFromFen(castlingRights); err != nil {
+		return nil, err
+	}
 	p.enPassanteSq = enPassanteSq
 	p.moveCt = moveCount
 	p.halfMoveCt = halfMoveCount
-	p.previousPos = nil
+	p.history = make([]undo, 0, historyCapacity)
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	p.hash = p.computeHash()
+	p.pawnHash = p.computePawnHash()
 	return p, nil
 }
 
+// Copy returns an independent position that can take back the same moves
 func (p *Position) Copy() *Position {
 	pCopy := new(Position)
 	*pCopy = *p
-	pCopy.bitboards[0] = make([]bitboard.Bitboard, 7)
-	pCopy.bitboards[1] = make([]bitboard.Bitboard, 7)
-	pCopy.castlingRights = make([]bitboard.Bitboard, 2)
-	copy(pCopy.bitboards[0], p.bitboards[0])
-	copy(pCopy.bitboards[1], p.bitboards[1])
-	copy(pCopy.castlingRights, p.castlingRights)
+	pCopy.history = make([]undo, len(p.history), len(p.history)+historyCapacity)
+	copy(pCopy.history, p.history)
 	return pCopy
 }
 
+// HalfMoveClock returns the number of half-moves since the last pawn move or capture
+func (p *Position) HalfMoveClock() int {
+	return p.halfMoveCt
+}
+
 // AllOccupiedSqsBb returns bitboard representing which squares havea piece
 func (p *Position) AllOccupiedSqsBb() *bitboard.Bitboard {
 	return bitboard.ReturnCombined(&p.bitboards[White][OccupiedSqs], &p.bitboards[Black][OccupiedSqs])
@@ -121,7 +157,7 @@ func (p *Position) GetActiveSideCastlingRightsBb() *bitboard.Bitboard {
 
 // GetActiveSidesBitboards returns the position bitboards for the currently active side
 func (p *Position) GetActiveSidesBitboards() []bitboard.Bitboard {
-	return p.bitboards[p.activeSide]
+	return p.bitboards[p.activeSide][:]
 }
 
 func (p *Position) ActiveSideKingBb() bitboard.Bitboard {
@@ -144,15 +180,75 @@ func (p *Position) BlackKingBb() bitboard.Bitboard {
 }
 
 func (p *Position) GetWhiteBitboards() []bitboard.Bitboard {
-	return p.bitboards[White]
+	return p.bitboards[White][:]
 }
 
 func (p *Position) GetBlackBitboards() []bitboard.Bitboard {
-	return p.bitboards[Black]
+	return p.bitboards[Black][:]
 }
 
+// UnMakeMove takes back the last move in place and returns the position,
+// or nil when there is no move to take back
 func (p *Position) UnMakeMove() *Position {
-	return p.previousPos
+	if len(p.history) == 0 {
+		return nil
+	}
+	record := &p.history[len(p.history)-1]
+	p.activeSide = 1 - p.activeSide
+	if !record.nullMove {
+		p.unMovePieces(record)
+	}
+	if p.activeSide == Black {
+		p.moveCt--
+	}
+	p.castlingRights = record.castlingRights
+	p.enPassanteSq = record.enPassanteSq
+	p.halfMoveCt = record.halfMoveCt
+	p.hash = record.hash
+	p.pawnHash = record.pawnHash
+	p.history = p.history[:len(p.history)-1]
+	return p
+}
+
+// unMovePieces puts the pieces moved by record back, the hashes are restored separately
+func (p *Position) unMovePieces(record *undo) {
+	origin, terminus := int(record.origin), int(record.terminus)
+	pieces := &p.bitboards[p.activeSide]
+	if record.promotionPiece != 0 {
+		pieces[record.promotionPiece].RemoveBit(terminus)
+		pieces[Pawns].SetBit(terminus)
+	}
+	pieces[record.movingPiece].RemoveBit(terminus)
+	pieces[record.movingPiece].SetBit(origin)
+	if record.movingPiece == King {
+		switch terminus - origin {
+		case 2:
+			pieces[Rooks].RemoveBit(terminus - 1)
+			pieces[Rooks].SetBit(terminus + 1)
+		case -2:
+			pieces[Rooks].RemoveBit(terminus + 1)
+			pieces[Rooks].SetBit(terminus - 2)
+		}
+	}
+	if record.capturedPiece != OccupiedSqs {
+		p.bitboards[1-p.activeSide][record.capturedPiece].SetBit(int(record.capturedSq))
+	}
+	p.updatedOccupiedSqBitboard(White)
+	p.updatedOccupiedSqBitboard(Black)
+}
+
+// pushUndo records the state that the move about to be made from origin to terminus changes
+func (p *Position) pushUndo(origin int, terminus int, nullMove bool) {
+	p.history = append(p.history, undo{
+		origin:         uint8(origin),
+		terminus:       uint8(terminus),
+		nullMove:       nullMove,
+		castlingRights: p.castlingRights,
+		enPassanteSq:   p.enPassanteSq,
+		halfMoveCt:     p.halfMoveCt,
+		hash:           p.hash,
+		pawnHash:       p.pawnHash,
+	})
 }
 
 func (p *Position) Move(mv moves.Move) {
@@ -164,20 +260,26 @@ func (p *Position) Move(mv moves.Move) {
 }
 
 func (p *Position) promotePawn(sq int, piece int, sideToMove int) {
+	p.history[len(p.history)-1].promotionPiece = uint8(piece)
 	p.bitboards[sideToMove][piece].SetBit(sq)
 	p.bitboards[sideToMove][Pawns].RemoveBit(sq)
+	p.togglePiece(sideToMove, Pawns, sq)
+	p.togglePiece(sideToMove, piece, sq)
 	p.updatedOccupiedSqBitboard(sideToMove)
 }
 
 func (p *Position) MakeMove(originIndex int, terminusIndex int) {
-	p.previousPos = p.Copy()
+	p.pushUndo(originIndex, terminusIndex, false)
 	// double pawn push move, set en passante
-	p.enPassanteSq = 64
+	p.setEnPassanteSq(64)
 	doublePawnPush := p.bitboards[p.activeSide][Pawns].BitIsSet(originIndex) && (terminusIndex-originIndex == -16 || terminusIndex-originIndex == 16)
 	if doublePawnPush {
-		p.enPassanteSq = (terminusIndex-originIndex)/2 + originIndex
+		p.setEnPassanteSq((terminusIndex-originIndex)/2 + originIndex)
 	}
 	movingPiece := p.updateMovingSidesBbs(originIndex, terminusIndex)
+	record := &p.history[len(p.history)-1]
+	record.movingPiece = uint8(movingPiece)
+	p.halfMoveCt++
 
 	if movingPiece == King {
 		diff := terminusIndex - originIndex
@@ -189,25 +291,31 @@ func (p *Position) MakeMove(originIndex int, terminusIndex int) {
 		if diff == -2 {
 			_ = p.updateMovingSidesBbs(terminusIndex-2, terminusIndex+1)
 		}
-		p.revokeQueenSideCastlingRight()
-		p.revokeKingSideCastlingRight()
+		p.revokeQueenSideCastlingRight(p.activeSide)
+		p.revokeKingSideCastlingRight(p.activeSide)
 	}
 	if movingPiece == Rooks {
-		if originIndex == 0 || originIndex == 56 {
-			p.revokeQueenSideCastlingRight()
+		if originIndex == queenSideRookSq[p.activeSide] {
+			p.revokeQueenSideCastlingRight(p.activeSide)
 		}
-		if originIndex == 7 || originIndex == 63 {
-			p.revokeKingSideCastlingRight()
+		if originIndex == kingSideRookSq[p.activeSide] {
+			p.revokeKingSideCastlingRight(p.activeSide)
 		}
 	}
 	p.updatedOccupiedSqBitboard(p.activeSide)
 	p.switchActiveSide()
 	attackedPiece := p.removeAttackedPieceFromBbs(terminusIndex)
+	record.capturedPiece, record.capturedSq = uint8(attackedPiece), uint8(terminusIndex)
+	// pawn moves and captures are irreversible and restart the fifty-move count
+	if movingPiece == Pawns || attackedPiece != 0 {
+		p.halfMoveCt = 0
+	}
+	// the captured side is now active
 	if attackedPiece == Rooks {
-		if (terminusIndex % 8) == 7 {
-			p.revokeKingSideCastlingRight()
-		} else if (terminusIndex % 8) == 0 {
-			p.revokeQueenSideCastlingRight()
+		if terminusIndex == kingSideRookSq[p.activeSide] {
+			p.revokeKingSideCastlingRight(p.activeSide)
+		} else if terminusIndex == queenSideRookSq[p.activeSide] {
+			p.revokeQueenSideCastlingRight(p.activeSide)
 		}
 	}
 	enPassanteAttack := movingPiece == Pawns && (terminusIndex-originIndex)%8 != 0 && attackedPiece == 0
@@ -217,9 +325,23 @@ func (p *Position) MakeMove(originIndex int, terminusIndex int) {
 			capturnedPawnIndex = terminusIndex - 8
 		}
 		p.removeAttackedPieceFromBbs(capturnedPawnIndex)
+		record.capturedPiece, record.capturedSq = Pawns, uint8(capturnedPawnIndex)
 	}
 	p.updatedOccupiedSqBitboard(p.activeSide)
-	if p.activeSide == Black {
+	if p.activeSide == White {
+		p.moveCt++
+	}
+}
+
+// MakeNullMove passes the turn to the other side without moving a piece.
+// It is not a legal chess move, the search uses it to test whether the side to move
+// could afford to do nothing. UnMakeMove takes it back like any other move.
+func (p *Position) MakeNullMove() {
+	p.pushUndo(0, 0, true)
+	p.setEnPassanteSq(64)
+	p.halfMoveCt++
+	p.switchActiveSide()
+	if p.activeSide == White {
 		p.moveCt++
 	}
 }
@@ -256,58 +378,48 @@ func (p *Position) switchActiveSide() {
 	} else {
 		p.activeSide = White
 	}
+	p.hash ^= zobrist.blackToMove
+}
+
+func (p *Position) setEnPassanteSq(sq int) {
+	p.hash ^= p.enPassanteKey()
+	p.enPassanteSq = sq
+	p.hash ^= p.enPassanteKey()
+}
+
+// pieces in the order they are looked for when moving or capturing
+var pieceLookupOrder = [6]int{Pawns, Rooks, Knights, Bishops, Queen, King}
+
+// PieceAt returns the piece of side on sq, or OccupiedSqs if side has no piece there
+func (p *Position) PieceAt(side int, sq int) int {
+	for _, piece := range pieceLookupOrder {
+		if p.bitboards[side][piece].BitIsSet(sq) {
+			return piece
+		}
+	}
+	return OccupiedSqs
 }
 
 func (p *Position) removeAttackedPieceFromBbs(terminus int) int {
-	switch {
-	case p.bitboards[p.activeSide][Pawns].BitIsSet(terminus):
-		p.bitboards[p.activeSide][Pawns].RemoveBit(terminus)
-		return Pawns
-	case p.bitboards[p.activeSide][Rooks].BitIsSet(terminus):
-		p.bitboards[p.activeSide][Rooks].RemoveBit(terminus)
-		return Rooks
-	case p.bitboards[p.activeSide][Knights].BitIsSet(terminus):
-		p.bitboards[p.activeSide][Knights].RemoveBit(terminus)
-		return Knights
-	case p.bitboards[p.activeSide][Bishops].BitIsSet(terminus):
-		p.bitboards[p.activeSide][Bishops].RemoveBit(terminus)
-		return Bishops
-	case p.bitboards[p.activeSide][Queen].BitIsSet(terminus):
-		p.bitboards[p.activeSide][Queen].RemoveBit(terminus)
-		return Queen
-	case p.bitboards[p.activeSide][King].BitIsSet(terminus):
-		p.bitboards[p.activeSide][King].RemoveBit(terminus)
-		return King
+	for _, piece := range pieceLookupOrder {
+		if p.bitboards[p.activeSide][piece].BitIsSet(terminus) {
+			p.bitboards[p.activeSide][piece].RemoveBit(terminus)
+			p.togglePiece(p.activeSide, piece, terminus)
+			return piece
+		}
 	}
 	return 0
 }
 
 func (p *Position) updateMovingSidesBbs(origin int, terminus int) int {
-	switch {
-	case p.bitboards[p.activeSide][Pawns].BitIsSet(origin):
-		p.bitboards[p.activeSide][Pawns].SetBit(terminus)
-		p.bitboards[p.activeSide][Pawns].RemoveBit(origin)
-		return Pawns
-	case p.bitboards[p.activeSide][Rooks].BitIsSet(origin):
-		p.bitboards[p.activeSide][Rooks].SetBit(terminus)
-		p.bitboards[p.activeSide][Rooks].RemoveBit(origin)
-		return Rooks
-	case p.bitboards[p.activeSide][Knights].BitIsSet(origin):
-		p.bitboards[p.activeSide][Knights].SetBit(terminus)
-		p.bitboards[p.activeSide][Knights].RemoveBit(origin)
-		return Knights
-	case p.bitboards[p.activeSide][Bishops].BitIsSet(origin):
-		p.bitboards[p.activeSide][Bishops].SetBit(terminus)
-		p.bitboards[p.activeSide][Bishops].RemoveBit(origin)
-		return Bishops
-	case p.bitboards[p.activeSide][Queen].BitIsSet(origin):
-		p.bitboards[p.activeSide][Queen].SetBit(terminus)
-		p.bitboards[p.activeSide][Queen].RemoveBit(origin)
-		return Queen
-	case p.bitboards[p.activeSide][King].BitIsSet(origin):
-		p.bitboards[p.activeSide][King].SetBit(terminus)
-		p.bitboards[p.activeSide][King].RemoveBit(origin)
-		return King
+	for _, piece := range pieceLookupOrder {
+		if p.bitboards[p.activeSide][piece].BitIsSet(origin) {
+			p.bitboards[p.activeSide][piece].SetBit(terminus)
+			p.bitboards[p.activeSide][piece].RemoveBit(origin)
+			p.togglePiece(p.activeSide, piece, origin)
+			p.togglePiece(p.activeSide, piece, terminus)
+			return piece
+		}
 	}
 	return 0
 }
@@ -321,8 +433,8 @@ func (p *Position) GetFenString() string {
 	fenPosition += " " + activeSideString +
 		" " + castlingRightsString +
 		" " + enPassanteSqFenString +
-		" " + strconv.Itoa(p.moveCt) +
-		" " + strconv.Itoa(p.halfMoveCt)
+		" " + strconv.Itoa(p.halfMoveCt) +
+		" " + strconv.Itoa(p.moveCt)
 	return fenPosition
 }
 
@@ -387,27 +499,35 @@ func (p *Position) setActiveSide(activeSide int) {
 	p.activeSide = activeSide
 }
 
-func (p *Position) revokeQueenSideCastlingRight() {
-	if p.activeSide == White && p.castlingRights[White].BitIsNotSet(WhiteKingSideCastlingRightsBit) {
-		p.castlingRights[p.activeSide].SetBit(WhiteQueenSideCastlingRightsBit)
-	}
-	p.castlingRights[p.activeSide].SetBit(BlackQueenSideCastlingRightsBit)
+// rook starting squares indexed by side, moving or capturing these rooks ends the castling right
+var queenSideRookSq = [2]int{56, 0}
+var kingSideRookSq = [2]int{63, 7}
+
+func (p *Position) revokeQueenSideCastlingRight(side int) {
+	p.hash ^= zobrist.castling[p.castlingIndex()]
+	p.castlingRights[side].SetBit([2]int{WhiteQueenSideCastlingRightsBit, BlackQueenSideCastlingRightsBit}[side])
+	p.hash ^= zobrist.castling[p.castlingIndex()]
 }
 
-func (p *Position) revokeKingSideCastlingRight() {
-	if p.activeSide == White {
-		p.castlingRights[p.activeSide].SetBit(WhiteKingSideCastlingRightsBit)
-	}
-	p.castlingRights[p.activeSide].SetBit(BlackKingSideCastlingRightsBit)
+func (p *Position) revokeKingSideCastlingRight(side int) {
+	p.hash ^= zobrist.castling[p.castlingIndex()]
+	p.castlingRights[side].SetBit([2]int{WhiteKingSideCastlingRightsBit, BlackKingSideCastlingRightsBit}[side])
+	p.hash ^= zobrist.castling[p.castlingIndex()]
 }
 
-func (p *Position) setCastlingRightsFromFen(castlingRights string) {
+func (p *Position) setCastlingRightsFromFen(castlingRights string) error {
 	p.castlingRights[White].SetBit(WhiteKingSideCastlingRightsBit)
 	p.castlingRights[White].SetBit(WhiteQueenSideCastlingRightsBit)
 	p.castlingRights[Black].SetBit(BlackKingSideCastlingRightsBit)
 	p.castlingRights[Black].SetBit(BlackQueenSideCastlingRightsBit)
+	if castlingRights == "-" {
+		return nil
+	}
 	for i := 0; i < len(castlingRights); i++ {
 		singleCastlingRight := string(castlingRights[i])
+		if strings.Count(castlingRights, singleCastlingRight) > 1 {
+			return fmt.Errorf("castling right %q repeated in FEN castling field %q", singleCastlingRight, castlingRights)
+		}
 		switch singleCastlingRight {
 		case "K":
 			p.castlingRights[White].RemoveBit(WhiteKingSideCastlingRightsBit)
@@ -417,88 +537,92 @@ func (p *Position) setCastlingRightsFromFen(castlingRights string) {
 			p.castlingRights[Black].RemoveBit(BlackKingSideCastlingRightsBit)
 		case "q":
 			p.castlingRights[Black].RemoveBit(BlackQueenSideCastlingRightsBit)
+		default:
+			return fmt.Errorf("invalid castling right %q in FEN castling field %q", singleCastlingRight, castlingRights)
 		}
 	}
+	return nil
 }
 
-func (p *Position) setBitboardsFromFen(fenPosition string, activeSide int) {
-	var boardIndex int
-	for fenStringIndex := 0; fenStringIndex < len(fenPosition); fenStringIndex++ {
-		letter := string(fenPosition[fenStringIndex])
-		switch letter {
-		case "p":
-			p.bitboards[Black][Pawns].SetBit(boardIndex)
-		case "r":
-			p.bitboards[Black][Rooks].SetBit(boardIndex)
-		case "n":
-			p.bitboards[Black][Knights].SetBit(boardIndex)
-		case "b":
-			p.bitboards[Black][Bishops].SetBit(boardIndex)
-		case "q":
-			p.bitboards[Black][Queen].SetBit(boardIndex)
-		case "k":
-			p.bitboards[Black][King].SetBit(boardIndex)
-		case "P":
-			p.bitboards[White][Pawns].SetBit(boardIndex)
-		case "R":
-			p.bitboards[White][Rooks].SetBit(boardIndex)
-		case "N":
-			p.bitboards[White][Knights].SetBit(boardIndex)
-		case "B":
-			p.bitboards[White][Bishops].SetBit(boardIndex)
-		case "Q":
-			p.bitboards[White][Queen].SetBit(boardIndex)
-		case "K":
-			p.bitboards[White][King].SetBit(boardIndex)
-		case "/":
-			boardIndex--
-		case "1":
-		case "2":
-			boardIndex++
-		case "3":
-			boardIndex += 2
-		case "4":
-			boardIndex += 3
-		case "5":
-			boardIndex += 4
-		case "6":
-			boardIndex += 5
-		case "7":
-			boardIndex += 6
-		case "8":
-			boardIndex += 7
+// fenPieces maps FEN piece letters to side and piece
+var fenPieces = map[rune][2]int{
+	'p': {Black, Pawns}, 'r': {Black, Rooks}, 'n': {Black, Knights}, 'b': {Black, Bishops}, 'q': {Black, Queen}, 'k': {Black, King},
+	'P': {White, Pawns}, 'R': {White, Rooks}, 'N': {White, Knights}, 'B': {White, Bishops}, 'Q': {White, Queen}, 'K': {White, King},
+}
+
+func (p *Position) setBitboardsFromFen(fenPosition string) error {
+	ranks := strings.Split(fenPosition, "/")
+	if len(ranks) != 8 {
+		return fmt.Errorf("FEN position %q has %d ranks, expected 8", fenPosition, len(ranks))
+	}
+	for i, rank := range ranks {
+		boardIndex := i * 8
+		squares := 0
+		for _, letter := range rank {
+			if letter >= '1' && letter <= '8' {
+				squares += int(letter - '0')
+				continue
+			}
+			piece, ok := fenPieces[letter]
+			if !ok {
+				return fmt.Errorf("invalid piece %q on rank %d of FEN position %q", letter, 8-i, fenPosition)
+			}
+			if squares < 8 {
+				p.bitboards[piece[0]][piece[1]].SetBit(boardIndex + squares)
+			}
+			squares++
+		}
+		if squares != 8 {
+			return fmt.Errorf("rank %d of FEN position %q covers %d squares, expected 8", 8-i, fenPosition, squares)
 		}
-		boardIndex++
 	}
 	p.updatedOccupiedSqBitboard(White)
 	p.updatedOccupiedSqBitboard(Black)
+	return nil
 }
 
-func getFenStringTokens(fen string) (string, int, string, int, int, int) {
+func getFenStringTokens(fen string) (string, int, string, int, int, int, error) {
 	var activeSide int
-	fenTokens := strings.Split(fen, " ")
-	moveCount, err := strconv.Atoi(fenTokens[4])
+	fenTokens := strings.Fields(fen)
+	if len(fenTokens) != 6 {
+		return "", 0, "", 0, 0, 0, fmt.Errorf("FEN %q has %d fields, expected 6", fen, len(fenTokens))
+	}
+	halfMoveCount, err := strconv.Atoi(fenTokens[4])
+	if err != nil {
+		return "", 0, "", 0, 0, 0, fmt.Errorf("invalid halfmove clock %q in FEN", fenTokens[4])
+	}
+	moveCount, err := strconv.Atoi(fenTokens[5])
 	if err != nil {
-		log.Fatal(err)
+		return "", 0, "", 0, 0, 0, fmt.Errorf("invalid move number %q in FEN", fenTokens[5])
 	}
-	halfMoveCount, err := strconv.Atoi(fenTokens[5])
+	enPassnantSq, err := parseEnPassanteSq(fenTokens[3])
 	if err != nil {
-		log.Fatal(err)
+		return "", 0, "", 0, 0, 0, err
 	}
-	enPassnantSq, _ := moves.ConvertAlgebriacToIndex(fenTokens[3])
 	switch fenTokens[1] {
 	case "w":
 		activeSide = White
 	case "b":
 		activeSide = Black
 	default:
-		log.Fatal(errors.New("Active side encoded in Fen must be either 'w' or 'b'"))
+		return "", 0, "", 0, 0, 0, fmt.Errorf("active side %q encoded in FEN must be either 'w' or 'b'", fenTokens[1])
 	}
 	err = validateFenTokens(fenTokens[0], activeSide, fenTokens[2], enPassnantSq, moveCount, halfMoveCount)
 	if err != nil {
-		log.Fatal(err)
+		return "", 0, "", 0, 0, 0, err
+	}
+	return fenTokens[0], activeSide, fenTokens[2], enPassnantSq, moveCount, halfMoveCount, nil
+}
+
+// parseEnPassanteSq accepts "-" or a square on the third or sixth rank
+func parseEnPassanteSq(sq string) (int, error) {
+	if sq == "-" {
+		return 64, nil
+	}
+	if len(sq) != 2 || sq[0] < 'a' || sq[0] > 'h' || (sq[1] != '3' && sq[1] != '6') {
+		return 0, fmt.Errorf("invalid en passante square %q in FEN", sq)
 	}
-	return fenTokens[0], activeSide, fenTokens[2], enPassnantSq, moveCount, halfMoveCount
+	return moves.ConvertAlgebriacToIndex(sq)
 }
 
 func validateFenTokens(Position string, activeSide int, castlingRights string, enPassanteSq int, moveCount int, halfMoveCount int) error {
@@ -585,7 +709,7 @@ func (p *Position) updatedOccupiedSqBitboard(activeSide int) {
 			p.bitboards[activeSide][Queen].Value())
 }
 
-func convertSingleBbRowToFenString(rank int, fenString *string, emptySqs *int, bb [2][]bitboard.Bitboard) {
+func convertSingleBbRowToFenString(rank int, fenString *string, emptySqs *int, bb [2][7]bitboard.Bitboard) {
 	for file := int(0); file < 8; file++ {
 		convertSingleIndexToString(file, rank, fenString, emptySqs, bb, defaultSingleIndexConversionToFenString)
 	}
@@ -599,7 +723,7 @@ func convertSingleBbRowToFenString(rank int, fenString *string, emptySqs *int, b
 	*emptySqs = 0
 }
 
-func convertBitboardsToFenString(bb [2][]bitboard.Bitboard) string {
+func convertBitboardsToFenString(bb [2][7]bitboard.Bitboard) string {
 	fenString := ""
 	emptySqs := 0
 	for rank := int(0); rank < 8; rank++ {
@@ -624,11 +748,11 @@ func (p *Position) Print() {
 	fmt.Printf("\nmove: %d, turn: %s\ncastling: %s, ep-file: %s\n\n", p.moveCt, turn, p.convertCastlingRightsToFenString(), p.convertEnPassanteSqToFenString())
 }
 
-func defaultSingleIndexConversionToCharacter(index int, file int, fenString *string, emptySqs *int, bb [2][]bitboard.Bitboard) {
+func defaultSingleIndexConversionToCharacter(index int, file int, fenString *string, emptySqs *int, bb [2][7]bitboard.Bitboard) {
 	*fenString += "."
 }
 
-func defaultSingleIndexConversionToFenString(index int, file int, fenString *string, emptySqs *int, bb [2][]bitboard.Bitboard) {
+func defaultSingleIndexConversionToFenString(index int, file int, fenString *string, emptySqs *int, bb [2][7]bitboard.Bitboard) {
 	*emptySqs++
 	nextWhiteSqOcc := bb[White][OccupiedSqs].BitIsSet(index + 1)
 	nexBlackSqOcc := bb[Black][OccupiedSqs].BitIsSet(index + 1)
@@ -639,7 +763,7 @@ func defaultSingleIndexConversionToFenString(index int, file int, fenString *str
 	}
 }
 
-func getRowString(rank int, fenString *string, emptySqs *int, bb [2][]bitboard.Bitboard) {
+func getRowString(rank int, fenString *string, emptySqs *int, bb [2][7]bitboard.Bitboard) {
 	for file := int(0); file < 8; file++ {
 		convertSingleIndexToString(file, rank, fenString, emptySqs, bb, defaultSingleIndexConversionToCharacter)
 		*fenString += " "
@@ -653,8 +777,8 @@ func getRowString(rank int, fenString *string, emptySqs *int, bb [2][]bitboard.B
 	*emptySqs = 0
 }
 
-func convertSingleIndexToString(i int, j int, fenString *string, emptySqs *int, bb [2][]bitboard.Bitboard,
-	defaultAction func(int, int, *string, *int, [2][]bitboard.Bitboard)) {
+func convertSingleIndexToString(i int, j int, fenString *string, emptySqs *int, bb [2][7]bitboard.Bitboard,
+	defaultAction func(int, int, *string, *int, [2][7]bitboard.Bitboard)) {
 	index := int(j*8 + i)
 	switch {
 	case bb[White][King].BitIsSet(index):
@@ -8,21 +8,23 @@ import (
 )
 
 func TestTokenizeFen(t *testing.T) {
-	position, activeSide, castlingRights, enPassante, moveCt, halfMoveCt := getFenStringTokens("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	position, activeSide, castlingRights, enPassante, moveCt, halfMoveCt, err := getFenStringTokens("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	assert.Nil(t, err)
 	assert.Equal(t, position, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR")
 	assert.Equal(t, activeSide, White)
 	assert.Equal(t, castlingRights, "KQkq")
 	assert.Equal(t, enPassante, 64)
-	assert.Equal(t, moveCt, 0)
-	assert.Equal(t, halfMoveCt, 1)
+	assert.Equal(t, moveCt, 1)
+	assert.Equal(t, halfMoveCt, 0)
 
-	position, activeSide, castlingRights, enPassante, moveCt, halfMoveCt = getFenStringTokens("rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b q e3 1 2")
+	position, activeSide, castlingRights, enPassante, moveCt, halfMoveCt, err = getFenStringTokens("rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b q e3 1 2")
+	assert.Nil(t, err)
 	assert.Equal(t, position, "rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R")
 	assert.Equal(t, activeSide, Black)
 	assert.Equal(t, castlingRights, "q")
 	assert.Equal(t, enPassante, 44)
-	assert.Equal(t, moveCt, 1)
-	assert.Equal(t, halfMoveCt, 2)
+	assert.Equal(t, moveCt, 2)
+	assert.Equal(t, halfMoveCt, 1)
 }
 
 func TestPositionContructorFen(t *testing.T) {
@@ -35,17 +37,52 @@ func TestPositionContructorFen(t *testing.T) {
 	position, _ = NewPositionFen("rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b - - 1 2")
 	assert.Equal(t, "rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b - - 1 2", position.GetFenString())
 
-	position, _ = NewPositionFen("7k/8/8/8/8/8/8/6KB w q - 0 1")
-	assert.Equal(t, "7k/8/8/8/8/8/8/6KB w q - 0 1", position.GetFenString())
+	position, _ = NewPositionFen("7k/8/8/8/8/8/8/6KB w - - 0 1")
+	assert.Equal(t, "7k/8/8/8/8/8/8/6KB w - - 0 1", position.GetFenString())
 
-	position, _ = NewPositionFen("7k/8/8/8/8/8/8/Rq6 w KQkq - 0 1")
-	assert.Equal(t, "7k/8/8/8/8/8/8/Rq6 w KQkq - 0 1", position.GetFenString())
+	position, _ = NewPositionFen("7k/8/8/8/8/8/8/Rq4K1 w - - 0 1")
+	assert.Equal(t, "7k/8/8/8/8/8/8/Rq4K1 w - - 0 1", position.GetFenString())
+}
+
+func TestNewPositionFenErrors(t *testing.T) {
+	tests := map[string]struct {
+		fen string
+		err string
+	}{
+		"empty string":                            {fen: "", err: `FEN "" has 0 fields, expected 6`},
+		"missing counters":                        {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -", err: `FEN "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq -" has 4 fields, expected 6`},
+		"extra field":                             {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1 1", err: `FEN "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1 1" has 7 fields, expected 6`},
+		"seven ranks":                             {fen: "rnbqkbnr/pppppppp/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", err: `FEN position "rnbqkbnr/pppppppp/8/8/8/PPPPPPPP/RNBQKBNR" has 7 ranks, expected 8`},
+		"unknown piece":                           {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNX w KQkq - 0 1", err: `invalid piece 'X' on rank 1 of FEN position "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNX"`},
+		"rank too long":                           {fen: "rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", err: `invalid piece '9' on rank 6 of FEN position "rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR"`},
+		"rank too short":                          {fen: "rnbqkbnr/ppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", err: `rank 7 of FEN position "rnbqkbnr/ppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR" covers 7 squares, expected 8`},
+		"invalid active side":                     {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1", err: `active side "x" encoded in FEN must be either 'w' or 'b'`},
+		"invalid castling right":                  {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkx - 0 1", err: `invalid castling right "x" in FEN castling field "KQkx"`},
+		"duplicate castling right":                {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KKkq - 0 1", err: `castling right "K" repeated in FEN castling field "KKkq"`},
+		"castling without rook":                   {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBN1 w KQkq - 0 1", err: `castling right "K" in FEN requires king and rook on their original squares`},
+		"castling with moved king":                {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQ1KNR w KQkq - 0 1", err: `castling right "K" in FEN requires king and rook on their original squares`},
+		"invalid en passante square":              {fen: "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e4 0 1", err: `invalid en passante square "e4" in FEN`},
+		"en passante square without pawn":         {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR b KQkq e3 0 1", err: "impossible en passante square e3 in FEN"},
+		"en passante square for the side to move": {fen: "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e3 0 1", err: "impossible en passante square e3 in FEN"},
+		"invalid halfmove clock":                  {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - x 1", err: `invalid halfmove clock "x" in FEN`},
+		"negative move number":                    {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 -1", err: "Move count encoded in FEN string is less than zero"},
+		"missing king":                            {fen: "rnbq1bnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQ - 0 1", err: "FEN position must have exactly one black king, found 0"},
+		"two kings":                               {fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBKKBNR w kq - 0 1", err: "FEN position must have exactly one white king, found 2"},
+		"pawn on the first rank":                  {fen: "4k3/8/8/8/8/8/8/P3K3 w - - 0 1", err: "FEN position has pawns on the first or eighth rank"},
+		"pawn on the eighth rank":                 {fen: "p3k3/8/8/8/8/8/8/4K3 w - - 0 1", err: "FEN position has pawns on the first or eighth rank"},
+		"side not to move in check":               {fen: "4k3/8/8/8/8/8/8/r3K3 b - - 0 1", err: "white is in check but it is black's turn"},
+	}
+	for tName, test := range tests {
+		pos, err := NewPositionFen(test.fen)
+		assert.EqualError(t, err, test.err, tName)
+		assert.Nil(t, pos, tName)
+	}
 }
 
 func TestPositionUpdate(t *testing.T) {
 	position, _ := NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
 	position.MakeMoveAlgebraic("e2", "e3")
-	assert.Equal(t, position.GetFenString(), "rnbqkbnr/pppppppp/8/8/8/4P3/PPPP1PPP/RNBQKBNR b KQkq - 1 1")
+	assert.Equal(t, position.GetFenString(), "rnbqkbnr/pppppppp/8/8/8/4P3/PPPP1PPP/RNBQKBNR b KQkq - 0 1")
 }
 
 func TestWhiteCanCastleKingSide(t *testing.T) {
@@ -88,23 +125,23 @@ func TestUnMakeMove(t *testing.T) {
 	position.MakeMoveAlgebraic("e2", "e3")
 	position.MakeMoveAlgebraic("e7", "e6")
 	position.MakeMoveAlgebraic("d2", "d4")
-	assert.Equal(t, "rnbqkbnr/pppp1ppp/4p3/8/3P4/4P3/PPP2PPP/RNBQKBNR b KQkq d3 2 1", position.GetFenString())
+	assert.Equal(t, "rnbqkbnr/pppp1ppp/4p3/8/3P4/4P3/PPP2PPP/RNBQKBNR b KQkq d3 0 2", position.GetFenString())
 	position = position.UnMakeMove()
 	position = position.UnMakeMove()
 	position = position.UnMakeMove()
 	assert.Equal(t, position.GetFenString(), "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
 
 	// unmake attacking move
-	position, _ = NewPositionFen("7k/8/8/8/8/8/7p/6KR w q - 0 1")
+	position, _ = NewPositionFen("7k/8/8/8/8/8/7p/6KR w - - 0 1")
 	position.MakeMoveAlgebraic("h1", "h2")
-	assert.Equal(t, position.GetFenString(), "7k/8/8/8/8/8/7R/6K1 b q - 1 1")
+	assert.Equal(t, position.GetFenString(), "7k/8/8/8/8/8/7R/6K1 b - - 0 1")
 	position = position.UnMakeMove()
-	assert.Equal(t, position.GetFenString(), "7k/8/8/8/8/8/7p/6KR w q - 0 1")
+	assert.Equal(t, position.GetFenString(), "7k/8/8/8/8/8/7p/6KR w - - 0 1")
 
 	//unmake en passante move
 	position, _ = NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
 	position.MakeMoveAlgebraic("e2", "e4")
-	assert.Equal(t, position.GetFenString(), "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 1 1")
+	assert.Equal(t, position.GetFenString(), "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1")
 	position = position.UnMakeMove()
 	assert.Equal(t, position.GetFenString(), "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
 }
@@ -128,12 +165,12 @@ func TestCastling(t *testing.T) {
 		"moving black king remove castling": {
 			pos:      "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R b KQkq - 0 1",
 			move:     [2]string{"e8", "g8"},
-			expected: "r4rk1/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQ - 0 1",
+			expected: "r4rk1/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQ - 1 2",
 		},
 		"moving black king removes castling rights 2": {
 			pos:      "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R b KQkq - 0 1",
 			move:     [2]string{"e8", "c8"},
-			expected: "2kr3r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQ - 0 1",
+			expected: "2kr3r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQ - 1 2",
 		},
 		"moving white rook removes queenside castling rights": {
 			pos:      "r3k2r/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
@@ -148,12 +185,32 @@ func TestCastling(t *testing.T) {
 		"moving black rook removes queenside castling rights": {
 			pos:      "r3k2r/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/R3K2R b KQkq - 0 1",
 			move:     [2]string{"a8", "b8"},
-			expected: "1r2k2r/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/R3K2R w KQk - 0 1",
+			expected: "1r2k2r/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/R3K2R w KQk - 1 2",
 		},
 		"moving black rook removes kingside castling rights": {
 			pos:      "r3k2r/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/R3K2R b KQkq - 0 1",
 			move:     [2]string{"h8", "g8"},
-			expected: "r3k1r1/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/R3K2R w KQq - 0 1",
+			expected: "r3k1r1/p1ppqNb1/bn2pnp1/3P4/4P3/2p2Q1p/PPPBBPPP/R3K2R w KQq - 1 2",
+		},
+		"moving white rook removes queenside castling rights without kingside rights": {
+			pos:      "r3k2r/8/8/8/8/8/8/R3K2R w Qkq - 0 1",
+			move:     [2]string{"a1", "b1"},
+			expected: "r3k2r/8/8/8/8/8/8/1R2K2R b kq - 1 1",
+		},
+		"capturing a rook in the corner removes its castling rights": {
+			pos:      "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1",
+			move:     [2]string{"a1", "a8"},
+			expected: "R3k2r/8/8/8/8/8/8/4K2R b Kk - 0 1",
+		},
+		"capturing a rook outside the corner keeps castling rights": {
+			pos:      "r3k2r/8/8/8/8/8/7r/R3K2R w KQkq - 0 1",
+			move:     [2]string{"h1", "h2"},
+			expected: "r3k2r/8/8/8/8/8/7R/R3K3 b Qkq - 0 1",
+		},
+		"black capturing a rook in the corner removes its castling rights": {
+			pos:      "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1",
+			move:     [2]string{"h8", "h1"},
+			expected: "r3k3/8/8/8/8/8/8/R3K2r w Qq - 0 2",
 		},
 	}
 	for tName, test := range tests {
@@ -168,7 +225,7 @@ func TestCastling(t *testing.T) {
 func TestEnPassanteAttackMove(t *testing.T) {
 	position, _ := NewPositionFen("r3k2r/p1ppqNb1/1n2pnp1/1b1P4/Pp2P3/2N2Q1p/1PPBBPPP/R3K2R b KQkq a3 0 1")
 	position.MakeMoveAlgebraic("b4", "a3")
-	assert.Equal(t, "r3k2r/p1ppqNb1/1n2pnp1/1b1P4/4P3/p1N2Q1p/1PPBBPPP/R3K2R w KQkq - 0 1", position.GetFenString())
+	assert.Equal(t, "r3k2r/p1ppqNb1/1n2pnp1/1b1P4/4P3/p1N2Q1p/1PPBBPPP/R3K2R w KQkq - 0 2", position.GetFenString())
 }
 
 func TestIsCastlingMove(t *testing.T) {
@@ -192,3 +249,127 @@ func TestIsCastlingMove(t *testing.T) {
 	mv = moves.NewMove([]int{60, 61})
 	assert.False(t, position.IsCastlingMove(*mv))
 }
+
+func TestHash(t *testing.T) {
+	start, _ := NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	position, _ := NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	position.MakeMoveAlgebraic("g1", "f3")
+	assert.NotEqual(t, start.Hash(), position.Hash())
+	position.MakeMoveAlgebraic("g8", "f6")
+	position.MakeMoveAlgebraic("f3", "g1")
+	position.MakeMoveAlgebraic("f6", "g8")
+	// same pieces, side and rights but different move counters
+	assert.Equal(t, start.Hash(), position.Hash())
+
+	blackToMove, _ := NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR b KQkq - 0 1")
+	assert.NotEqual(t, start.Hash(), blackToMove.Hash())
+	noCastling, _ := NewPositionFen("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w - - 0 1")
+	assert.NotEqual(t, start.Hash(), noCastling.Hash())
+	enPassante, _ := NewPositionFen("rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1")
+	noEnPassante, _ := NewPositionFen("rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1")
+	assert.NotEqual(t, noEnPassante.Hash(), enPassante.Hash())
+	// an en passante square no pawn can capture on does not change the position
+	enPassante, _ = NewPositionFen("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1")
+	noEnPassante, _ = NewPositionFen("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1")
+	assert.Equal(t, noEnPassante.Hash(), enPassante.Hash())
+}
+
+func TestPieceAt(t *testing.T) {
+	position, _ := NewPositionFen("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1")
+	assert.Equal(t, Queen, position.PieceAt(Black, 3))
+	assert.Equal(t, Knights, position.PieceAt(White, 62))
+	assert.Equal(t, Pawns, position.PieceAt(White, 36))
+	assert.Equal(t, OccupiedSqs, position.PieceAt(Black, 36))
+	assert.Equal(t, OccupiedSqs, position.PieceAt(White, 28))
+}
+
+func TestHalfMoveClock(t *testing.T) {
+	tests := map[string]struct {
+		pos      string
+		move     [2]string
+		clock    int
+		expected string
+	}{
+		"quiet piece move counts": {
+			pos:      "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			move:     [2]string{"g1", "f3"},
+			clock:    1,
+			expected: "rnbqkbnr/pppppppp/8/8/8/5N2/PPPPPPPP/RNBQKB1R b KQkq - 1 1",
+		},
+		"black move increments the move number": {
+			pos:      "4k3/8/8/8/8/8/8/R3K3 b - - 37 80",
+			move:     [2]string{"e8", "d8"},
+			clock:    38,
+			expected: "3k4/8/8/8/8/8/8/R3K3 w - - 38 81",
+		},
+		"pawn move resets the clock": {
+			pos:      "4k3/8/8/8/8/8/4P3/4K3 w - - 12 40",
+			move:     [2]string{"e2", "e3"},
+			clock:    0,
+			expected: "4k3/8/8/8/8/4P3/8/4K3 b - - 0 40",
+		},
+		"capture resets the clock": {
+			pos:      "4k3/8/8/8/8/8/4r3/R3K3 w - - 99 60",
+			move:     [2]string{"e1", "e2"},
+			clock:    0,
+			expected: "4k3/8/8/8/8/8/4K3/R7 b - - 0 60",
+		},
+	}
+	for tName, test := range tests {
+		position, _ := NewPositionFen(test.pos)
+		position.MakeMoveAlgebraic(test.move[0], test.move[1])
+		assert.Equal(t, test.clock, position.HalfMoveClock(), tName)
+		assert.Equal(t, test.expected, position.GetFenString(), tName)
+		position = position.UnMakeMove()
+		assert.Equal(t, test.pos, position.GetFenString(), tName)
+	}
+}
+
+func TestRepetition(t *testing.T) {
+	position := StartingPosition()
+	shuffle := [][2]string{{"g1", "f3"}, {"g8", "f6"}, {"f3", "g1"}, {"f6", "g8"}}
+	for round := 0; round < 2; round++ {
+		for i, move := range shuffle {
+			assert.False(t, position.IsThreefoldRepetition(), "round %d move %d", round, i)
+			position.MakeMoveAlgebraic(move[0], move[1])
+		}
+		assert.True(t, position.IsRepetition(), "round %d", round)
+	}
+	assert.True(t, position.IsThreefoldRepetition())
+
+	// a pawn move makes the earlier positions unreachable
+	position.MakeMoveAlgebraic("e2", "e4")
+	position.MakeMoveAlgebraic("e7", "e5")
+	assert.False(t, position.IsRepetition())
+	position.MakeMoveAlgebraic("g1", "f3")
+	position.MakeMoveAlgebraic("g8", "f6")
+	position.MakeMoveAlgebraic("f3", "g1")
+	position.MakeMoveAlgebraic("f6", "g8")
+	assert.True(t, position.IsRepetition())
+	assert.False(t, position.IsThreefoldRepetition())
+
+	// the same placement with the other side to move is a different position
+	position, _ = NewPositionFen("4k3/8/8/8/8/8/8/R3K3 w - - 0 1")
+	position.MakeMoveAlgebraic("a1", "a2")
+	position.MakeMoveAlgebraic("e8", "d8")
+	position.MakeMoveAlgebraic("a2", "a1")
+	assert.False(t, position.IsRepetition())
+}
+
+func TestMakeNullMove(t *testing.T) {
+	fen := "rnbqkbnr/pppp1ppp/8/8/3Pp3/8/PPP1PPPP/RNBQKBNR b KQkq d3 0 2"
+	position, _ := NewPositionFen(fen)
+	position.MakeNullMove()
+	assert.Equal(t, "rnbqkbnr/pppp1ppp/8/8/3Pp3/8/PPP1PPPP/RNBQKBNR w KQkq - 1 3", position.GetFenString())
+	assert.Equal(t, position.computeHash(), position.Hash())
+	position = position.UnMakeMove()
+	assert.Equal(t, fen, position.GetFenString())
+
+	// positions before a null move do not count as repetitions
+	position, _ = NewPositionFen("4k3/8/8/8/8/8/8/R3K3 w - - 0 1")
+	position.MakeMoveAlgebraic("e1", "d1")
+	position.MakeNullMove()
+	position.MakeMoveAlgebraic("d1", "e1")
+	position.MakeNullMove()
+	assert.False(t, position.IsRepetition())
+}
@@ -0,0 +1,32 @@
+package position
+
+// repetitions counts how often the current position occurred earlier in the game.
+// Only positions with the same side to move since the last pawn move or capture can repeat,
+// so the walk back through the history stops after halfMoveCt half-moves.
+// It also stops at a null move, positions before it were not reached by legal play.
+func (p *Position) repetitions() int {
+	count := 0
+	for halfMoves := 1; halfMoves <= len(p.history) && halfMoves <= p.halfMoveCt; halfMoves++ {
+		// the undo record of a move holds the hash of the position it was made from
+		record := &p.history[len(p.history)-halfMoves]
+		if record.nullMove {
+			break
+		}
+		if halfMoves%2 == 0 && record.hash == p.hash {
+			count++
+		}
+	}
+	return count
+}
+
+// IsRepetition reports whether the position occurred before in the game.
+// The search scores such positions as draws, since a side that could avoid
+// repeating would have done so the first time.
+func (p *Position) IsRepetition() bool {
+	return p.repetitions() > 0
+}
+
+// IsThreefoldRepetition reports whether the position occurred for the third time, which draws the game
+func (p *Position) IsThreefoldRepetition() bool {
+	return p.repetitions() >= 2
+}
@@ -0,0 +1,81 @@
+package position_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/engine"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// unmakeTestPositions cover castling, promotions and en passante captures
+var unmakeTestPositions = map[string]string{
+	"kiwipete":    "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"promotions":  "n1n5/PPPk4/8/8/8/8/4Kppp/5N1N b - - 0 1",
+	"en passante": "r3k2r/p1ppqNb1/1n2pnp1/1b1P4/Pp2P3/2N2Q1p/1PPBBPPP/R3K2R b KQkq a3 0 1",
+}
+
+// checkUnMake walks the move tree asserting that taking back each move restores the position exactly
+func checkUnMake(t *testing.T, pos *position.Position, depth int) {
+	if depth == 0 {
+		return
+	}
+	fen, hash := pos.GetFenString(), pos.Hash()
+	for _, move := range generate.GenerateMoves(pos).GetMovesList() {
+		if !engine.MakeValidMove(move, &pos) {
+			continue
+		}
+		checkUnMake(t, pos, depth-1)
+		assert.True(t, pos == pos.UnMakeMove())
+		assert.Equal(t, fen, pos.GetFenString(), move.String())
+		assert.Equal(t, hash, pos.Hash(), move.String())
+	}
+}
+
+func TestUnMakeRestoresPosition(t *testing.T) {
+	for tName, fen := range unmakeTestPositions {
+		pos, _ := position.NewPositionFen(fen)
+		t.Run(tName, func(t *testing.T) {
+			checkUnMake(t, pos, 3)
+		})
+	}
+	pos := position.StartingPosition()
+	assert.Nil(t, pos.UnMakeMove())
+}
+
+func TestMakeUnMakeDoesNotAllocate(t *testing.T) {
+	for tName, fen := range unmakeTestPositions {
+		pos, _ := position.NewPositionFen(fen)
+		mvs := generate.GenerateMoves(pos).GetMovesList()
+		allocs := testing.AllocsPerRun(100, func() {
+			for _, move := range mvs {
+				pos.Move(move)
+				pos.MakeNullMove()
+				pos.UnMakeMove()
+				pos.UnMakeMove()
+			}
+		})
+		assert.Equal(t, float64(0), allocs, tName)
+	}
+}
+
+func benchmarkMakeUnMake(b *testing.B, fen string) {
+	pos, _ := position.NewPositionFen(fen)
+	mvs := generate.GenerateMoves(pos).GetMovesList()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		move := mvs[i%len(mvs)]
+		pos.Move(move)
+		pos.UnMakeMove()
+	}
+}
+
+func BenchmarkMakeUnMakeKiwipete(b *testing.B) {
+	benchmarkMakeUnMake(b, unmakeTestPositions["kiwipete"])
+}
+
+func BenchmarkMakeUnMakePromotions(b *testing.B) {
+	benchmarkMakeUnMake(b, unmakeTestPositions["promotions"])
+}
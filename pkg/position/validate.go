@@ -0,0 +1,86 @@
+package position
+
+import (
+	"errors"
+	"fmt"
+)
+
+// validate rejects positions that cannot arise in a game, once all FEN fields are set
+func (p *Position) validate() error {
+	sideNames := [2]string{"white", "black"}
+	for side := White; side <= Black; side++ {
+		if kings := p.bitboards[side][King].PopulationCount(); kings != 1 {
+			return fmt.Errorf("FEN position must have exactly one %s king, found %d", sideNames[side], kings)
+		}
+	}
+	pawnsBb := p.bitboards[White][Pawns].Value() | p.bitboards[Black][Pawns].Value()
+	if pawnsBb&(ht.FirstRankBb|ht.EighthRankBb) != 0 {
+		return errors.New("FEN position has pawns on the first or eighth rank")
+	}
+	if err := p.validateCastlingRights(); err != nil {
+		return err
+	}
+	if err := p.validateEnPassanteSq(); err != nil {
+		return err
+	}
+	inactiveKingBb := p.InactiveSideKingBb()
+	if p.isAttackedBy(inactiveKingBb.Lsb(), p.activeSide) {
+		return fmt.Errorf("%s is in check but it is %s's turn", sideNames[1-p.activeSide], sideNames[p.activeSide])
+	}
+	return nil
+}
+
+// validateCastlingRights requires king and rook on their original squares for every castling right
+func (p *Position) validateCastlingRights() error {
+	rights := []struct {
+		allowed bool
+		side    int
+		king    int
+		rook    int
+		fen     string
+	}{
+		{p.WhiteCanCastleKingSide(), White, 60, 63, "K"},
+		{p.WhiteCanCastleQueenSide(), White, 60, 56, "Q"},
+		{p.BlackCanCastleKingSide(), Black, 4, 7, "k"},
+		{p.BlackCanCastleQueenSide(), Black, 4, 0, "q"},
+	}
+	for _, right := range rights {
+		if right.allowed && (p.bitboards[right.side][King].BitIsNotSet(right.king) || p.bitboards[right.side][Rooks].BitIsNotSet(right.rook)) {
+			return fmt.Errorf("castling right %q in FEN requires king and rook on their original squares", right.fen)
+		}
+	}
+	return nil
+}
+
+// validateEnPassanteSq requires the en passante square to be right behind a pawn
+// that could just have been pushed two squares by the side not to move
+func (p *Position) validateEnPassanteSq() error {
+	if p.enPassanteSq == 64 {
+		return nil
+	}
+	pushedPawnSq, originSq, pushingSide := p.enPassanteSq-8, p.enPassanteSq+8, White
+	if p.activeSide == White {
+		pushedPawnSq, originSq, pushingSide = p.enPassanteSq+8, p.enPassanteSq-8, Black
+	}
+	occupied := p.AllOccupiedSqsBb()
+	if p.enPassanteSq/8 != [2]int{2, 5}[p.activeSide] ||
+		p.bitboards[pushingSide][Pawns].BitIsNotSet(pushedPawnSq) ||
+		occupied.BitIsSet(p.enPassanteSq) || occupied.BitIsSet(originSq) {
+		return fmt.Errorf("impossible en passante square %s in FEN", convertIndexToAlgebraic(p.enPassanteSq))
+	}
+	return nil
+}
+
+// isAttackedBy reports whether a piece of side attacks sq. It mirrors generate.AttackersOf,
+// which cannot be used here without an import cycle.
+func (p *Position) isAttackedBy(sq int, side int) bool {
+	pieces := p.bitboards[side]
+	straight := pieces[Rooks].Value() | pieces[Queen].Value()
+	diagonal := pieces[Bishops].Value() | pieces[Queen].Value()
+	occupied := p.AllOccupiedSqsBb().Value()
+	return ht.PawnAttackBbHash[1-side][sq]&pieces[Pawns].Value() != 0 ||
+		ht.KnightAttackBbHash[sq]&pieces[Knights].Value() != 0 ||
+		ht.LegalKingMovesNoCastlingBbHash[sq]&pieces[King].Value() != 0 ||
+		ht.RookAttacks(sq, occupied)&straight != 0 ||
+		ht.BishopAttacks(sq, occupied)&diagonal != 0
+}
@@ -0,0 +1,135 @@
+package position
+
+import "math/bits"
+
+// zobristKeys holds the random numbers xor-ed together to form a position hash.
+// A position's key is the xor of one number per piece on its square,
+// one for the castling rights, one for the en passante file and one if black is to move.
+type zobristKeys struct {
+	pieces      [2][7][64]uint64
+	castling    [16]uint64
+	enPassante  [8]uint64
+	blackToMove uint64
+}
+
+// fixed seed so that hashes are reproducible between runs
+const zobristSeed = 0x9E3779B97F4A7C15
+
+var zobrist = newZobristKeys()
+
+func newZobristKeys() *zobristKeys {
+	keys := new(zobristKeys)
+	state := uint64(zobristSeed)
+	for side := White; side <= Black; side++ {
+		for piece := King; piece <= Pawns; piece++ {
+			for sq := 0; sq < 64; sq++ {
+				keys.pieces[side][piece][sq] = xorshift(&state)
+			}
+		}
+	}
+	for i := range keys.castling {
+		keys.castling[i] = xorshift(&state)
+	}
+	for i := range keys.enPassante {
+		keys.enPassante[i] = xorshift(&state)
+	}
+	keys.blackToMove = xorshift(&state)
+	return keys
+}
+
+// xorshift is the xorshift64* pseudo random number generator
+func xorshift(state *uint64) uint64 {
+	*state ^= *state >> 12
+	*state ^= *state << 25
+	*state ^= *state >> 27
+	return *state * 2685821657736338717
+}
+
+// Hash returns the Zobrist key of the position
+func (p *Position) Hash() uint64 {
+	return p.hash
+}
+
+// PawnHash returns the Zobrist key of the pawns alone, which identifies the pawn structure
+func (p *Position) PawnHash() uint64 {
+	return p.pawnHash
+}
+
+// computeHash calculates the Zobrist key from scratch
+func (p *Position) computeHash() uint64 {
+	var hash uint64
+	for side := White; side <= Black; side++ {
+		for piece := King; piece <= Pawns; piece++ {
+			bb := p.bitboards[side][piece].Value()
+			for bb != 0 {
+				sq := bits.TrailingZeros64(bb)
+				bb &= bb - 1
+				hash ^= zobrist.pieces[side][piece][sq]
+			}
+		}
+	}
+	hash ^= zobrist.castling[p.castlingIndex()]
+	hash ^= p.enPassanteKey()
+	if p.activeSide == Black {
+		hash ^= zobrist.blackToMove
+	}
+	return hash
+}
+
+// computePawnHash calculates the key of the pawns from scratch
+func (p *Position) computePawnHash() uint64 {
+	var hash uint64
+	for side := White; side <= Black; side++ {
+		bb := p.bitboards[side][Pawns].Value()
+		for bb != 0 {
+			sq := bits.TrailingZeros64(bb)
+			bb &= bb - 1
+			hash ^= zobrist.pieces[side][Pawns][sq]
+		}
+	}
+	return hash
+}
+
+// castlingIndex packs the four castling rights into a number from 0 to 15
+func (p *Position) castlingIndex() int {
+	index := 0
+	if p.WhiteCanCastleKingSide() {
+		index |= 1
+	}
+	if p.WhiteCanCastleQueenSide() {
+		index |= 2
+	}
+	if p.BlackCanCastleKingSide() {
+		index |= 4
+	}
+	if p.BlackCanCastleQueenSide() {
+		index |= 8
+	}
+	return index
+}
+
+// enPassanteKey only distinguishes positions by en passante square when a pawn can capture there,
+// otherwise a double pawn push would hide a repetition of the same position.
+// The capturing pawns do not move while the square is set, so the key is the same when it is cleared.
+func (p *Position) enPassanteKey() uint64 {
+	if p.enPassanteSq == 64 {
+		return 0
+	}
+	capturingSide := White
+	if p.enPassanteSq >= 40 {
+		capturingSide = Black
+	}
+	// capturing pawns stand where a pawn of the other side on the square would attack
+	if ht.PawnAttackBbHash[1-capturingSide][p.enPassanteSq]&p.bitboards[capturingSide][Pawns].Value() == 0 {
+		return 0
+	}
+	return zobrist.enPassante[p.enPassanteSq%8]
+}
+
+// togglePiece adds or removes a piece from the hash, and from the pawn hash if it is a pawn
+func (p *Position) togglePiece(side int, piece int, sq int) {
+	p.hash ^= zobrist.pieces[side][piece][sq]
+	if piece == Pawns {
+		p.pawnHash ^= zobrist.pieces[side][piece][sq]
+	}
+}
@@ -0,0 +1,62 @@
+package position_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/engine"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// checkHashes walks the legal move tree asserting that the incrementally updated hashes
+// always equal the ones computed from scratch for the same position
+func checkHashes(t *testing.T, pos **position.Position, depth int) {
+	fromScratch, _ := position.NewPositionFen((*pos).GetFenString())
+	assert.Equal(t, fromScratch.Hash(), (*pos).Hash(), (*pos).GetFenString())
+	assert.Equal(t, fromScratch.PawnHash(), (*pos).PawnHash(), (*pos).GetFenString())
+	if depth == 0 {
+		return
+	}
+	hash, pawnHash := (*pos).Hash(), (*pos).PawnHash()
+	for _, move := range generate.GenerateMoves(*pos).GetMovesList() {
+		if !engine.MakeValidMove(move, pos) {
+			continue
+		}
+		checkHashes(t, pos, depth-1)
+		*pos = (*pos).UnMakeMove()
+		assert.Equal(t, hash, (*pos).Hash())
+		assert.Equal(t, pawnHash, (*pos).PawnHash())
+	}
+}
+
+func TestIncrementalHashMatchesRecomputation(t *testing.T) {
+	tests := map[string]struct {
+		fen   string
+		depth int
+	}{
+		"starting position": {"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", 3},
+		"kiwipete":          {"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 2},
+		"promotions":        {"n1n5/PPPk4/8/8/8/8/4Kppp/5N1N b - - 0 1", 3},
+		"en passante":       {"r3k2r/p1ppqNb1/1n2pnp1/1b1P4/Pp2P3/2N2Q1p/1PPBBPPP/R3K2R b KQkq a3 0 1", 2},
+	}
+	for tName, test := range tests {
+		pos, _ := position.NewPositionFen(test.fen)
+		t.Run(tName, func(t *testing.T) {
+			checkHashes(t, &pos, test.depth)
+		})
+	}
+}
+
+func TestPawnHash(t *testing.T) {
+	pos := position.StartingPosition()
+	pawnHash := pos.PawnHash()
+	// piece moves leave the pawn structure alone
+	pos.MakeMoveAlgebraic("g1", "f3")
+	assert.Equal(t, pawnHash, pos.PawnHash())
+	pos.MakeMoveAlgebraic("e7", "e5")
+	assert.NotEqual(t, pawnHash, pos.PawnHash())
+	// the same pawns reached through different moves hash the same
+	other, _ := position.NewPositionFen("rnbqkbnr/pppp1ppp/8/4p3/8/5N2/PPPPPPPP/RNBQKB1R w KQkq - 0 1")
+	assert.Equal(t, other.PawnHash(), pos.PawnHash())
+}
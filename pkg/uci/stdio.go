@@ -0,0 +1,33 @@
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Run speaks UCI over a pair of streams, typically stdin and stdout,
+// until the GUI sends quit or the input is closed.
+func Run(in io.Reader, out io.Writer) {
+	var mu sync.Mutex
+	session := NewSession(func(msg string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := fmt.Fprintln(out, msg); err != nil {
+			log.Println("write:", err)
+		}
+	})
+	defer session.Close()
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if !session.Execute(scanner.Text()) {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Println("read error:", err)
+	}
+}
@@ -0,0 +1,199 @@
+// Package uci implements the Universal Chess Interface protocol
+// independently of the transport used to talk to the GUI.
+package uci
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tonyOreglia/glee/pkg/engine"
+	"github.com/tonyOreglia/glee/pkg/perft"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// Session holds the state of a single UCI conversation.
+// Front ends feed it one command line at a time and supply
+// the function used to send responses back to the GUI.
+// Searches run in their own goroutine, so write must be safe for concurrent use.
+type Session struct {
+	pos   *position.Position
+	write func(string)
+	// tt is kept between searches so that later moves of a game benefit from earlier ones
+	tt *engine.TranspositionTable
+	// options select the selective search techniques, each can be switched off with setoption
+	options engine.SearchOptions
+	// timer and searchDone belong to the running search, timer is nil when idle
+	timer      *engine.TimeManager
+	searchDone chan struct{}
+}
+
+// NewSession creates a session starting from the initial chess position
+func NewSession(write func(string)) *Session {
+	return &Session{
+		pos:     position.StartingPosition(),
+		write:   write,
+		tt:      engine.NewTranspositionTable(engine.DefaultHashSize),
+		options: engine.DefaultSearchOptions(),
+	}
+}
+
+// Execute handles a single UCI command line. It returns false once the GUI has asked to quit.
+func (s *Session) Execute(commandLine string) bool {
+	commandTokens := strings.Fields(commandLine)
+	if len(commandTokens) == 0 {
+		return true
+	}
+	command := commandTokens[0]
+	switch command {
+	case "uci":
+		log.Info("executing uci response")
+		s.write("GLEE-GoLang chEss Engine")
+		s.write("tony.oreglia@gmail.com")
+		s.write("id name GLEE (GoLang chEss Engine) 0.0.1")
+		s.write("id author Tony Oreglia")
+		s.write(fmt.Sprintf("option name Hash type spin default %d min 1 max %d", engine.DefaultHashSize, maxHashSize))
+		for _, option := range s.checkOptions() {
+			s.write(fmt.Sprintf("option name %s type check default %t", option.name, *option.value))
+		}
+		s.write("uciok")
+	case "debug":
+		s.write("not yet implemented")
+	case "isready":
+		s.write("readyok")
+	case "setoption":
+		s.stopSearch()
+		s.setOption(commandTokens)
+	case "register":
+		s.write("not yet implemented")
+	case "ucinewgame":
+		s.stopSearch()
+		s.pos = position.StartingPosition()
+		s.tt.Clear()
+	case "position":
+		log.Info("setting engine position")
+		s.stopSearch()
+		s.pos = setPosition(s.pos, commandTokens)
+		log.Debugf("position set to %s", s.pos.GetFenString())
+	case "go":
+		log.Info("calculating best move")
+		s.stopSearch()
+		params := parseGo(commandTokens)
+		if params.perft > 0 {
+			s.divide(params.perft)
+			break
+		}
+		// the game is drawn, like the command line play loop there is no move left to search for
+		if s.pos.IsThreefoldRepetition() {
+			s.write("info string draw by threefold repetition")
+			s.write("bestmove 0000")
+			break
+		}
+		s.startSearch(params)
+	case "stop":
+		s.stopSearch()
+	case "ponderhit":
+		if s.timer != nil {
+			s.timer.PonderHit()
+		}
+	case "quit":
+		s.Close()
+		return false
+	default:
+		s.write(fmt.Sprintf("Not yet implemented: %s", command))
+	}
+	return true
+}
+
+// Close stops any running search. Front ends call it when the GUI goes away without quitting.
+func (s *Session) Close() {
+	s.stopSearch()
+}
+
+// startSearch searches a copy of the current position in the background
+// and writes bestmove once the search is done and allowed to report.
+func (s *Session) startSearch(params goParams) {
+	pos := s.pos.Copy()
+	limits := searchLimits(params, pos.GetActiveSide())
+	done := make(chan struct{})
+	s.timer, s.searchDone = limits.Timer, done
+	go func() {
+		defer close(done)
+		result := engine.Search(pos, limits, s.options, s.tt, func(info engine.SearchInfo) {
+			s.write(formatInfo(info))
+		})
+		limits.Timer.WaitForRelease()
+		log.Infof("found best move %s", result.BestMove.String())
+		s.write(formatBestMove(result.BestMove, result.PV))
+	}()
+}
+
+// divide writes the perft count of every legal move followed by the total
+func (s *Session) divide(depth int) {
+	total := 0
+	for _, count := range perft.Divide(s.pos, depth) {
+		s.write(fmt.Sprintf("%s: %d", count.Move.String(), count.Nodes))
+		total += count.Nodes
+	}
+	s.write("")
+	s.write(fmt.Sprintf("Nodes searched: %d", total))
+}
+
+// setOption handles "setoption name <id> [value <x>]"
+func (s *Session) setOption(tokens []string) {
+	name, value := parseSetOption(tokens)
+	switch strings.ToLower(name) {
+	case "hash":
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 || size > maxHashSize {
+			badInput(strings.Join(tokens, " "))
+			return
+		}
+		s.tt = engine.NewTranspositionTable(size)
+	default:
+		s.setCheckOption(name, value, tokens)
+	}
+}
+
+// setCheckOption switches an on/off option, its value is "true" or "false"
+func (s *Session) setCheckOption(name string, value string, tokens []string) {
+	for _, option := range s.checkOptions() {
+		if strings.EqualFold(name, option.name) {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				badInput(strings.Join(tokens, " "))
+				return
+			}
+			*option.value = enabled
+			return
+		}
+	}
+	log.Warnf("unknown option: %s", name)
+}
+
+// checkOption is an on/off option, value points at the setting it controls
+type checkOption struct {
+	name  string
+	value *bool
+}
+
+// checkOptions lists the on/off options of the session
+func (s *Session) checkOptions() []checkOption {
+	return []checkOption{
+		{"NullMove", &s.options.NullMove},
+		{"LMR", &s.options.LMR},
+		{"CheckExtensions", &s.options.CheckExtensions},
+		{"PVS", &s.options.PVS},
+	}
+}
+
+// stopSearch ends the running search, if any, and waits for it to report its move
+func (s *Session) stopSearch() {
+	if s.timer == nil {
+		return
+	}
+	s.timer.Stop()
+	<-s.searchDone
+	s.timer = nil
+}
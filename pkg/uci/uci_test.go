@@ -0,0 +1,291 @@
+package uci
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tonyOreglia/glee/pkg/engine"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+func TestSetPosition(t *testing.T) {
+	tests := map[string]struct {
+		command  string
+		expected string
+	}{
+		"start position": {
+			command:  "position startpos",
+			expected: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		},
+		"start position with moves": {
+			command:  "position startpos moves e2e4 e7e5 g1f3",
+			expected: "rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2",
+		},
+		"fen keyword": {
+			command:  "position fen 7k/8/8/8/8/8/8/6KB w - - 0 1",
+			expected: "7k/8/8/8/8/8/8/6KB w - - 0 1",
+		},
+		"bare fen as sent by the web frontend": {
+			command:  "position 7k/8/8/8/8/8/8/6KB w - - 0 1",
+			expected: "7k/8/8/8/8/8/8/6KB w - - 0 1",
+		},
+		"lower case promotion": {
+			command:  "position fen 7k/P7/8/8/8/8/8/6K1 w - - 0 1 moves a7a8n",
+			expected: "N6k/8/8/8/8/8/8/6K1 b - - 0 1",
+		},
+		"illegal move leaves position untouched": {
+			command:  "position fen 7k/8/8/8/8/8/8/6KB w - - 0 1 moves h1a1",
+			expected: "7k/8/8/8/8/8/8/6KB w - - 0 1",
+		},
+	}
+	for tName, test := range tests {
+		session := NewSession(func(string) {})
+		session.Execute(test.command)
+		assert.Equal(t, test.expected, session.pos.GetFenString(), tName)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	var output []string
+	session := NewSession(func(msg string) {
+		output = append(output, msg)
+	})
+	assert.True(t, session.Execute("isready"))
+	assert.Equal(t, []string{"readyok"}, output)
+
+	output = nil
+	assert.True(t, session.Execute("uci"))
+	assert.Equal(t, "uciok", output[len(output)-1])
+	assert.Contains(t, output, "option name Hash type spin default 16 min 1 max 1024")
+	assert.Contains(t, output, "option name NullMove type check default true")
+	assert.Contains(t, output, "option name PVS type check default true")
+
+	output = nil
+	session.Execute("position fen 7k/8/8/8/8/8/8/R5K1 w - - 0 1")
+	session.Execute("go")
+	<-session.searchDone
+	output = withoutInfo(output)
+	assert.Equal(t, 1, len(output))
+	assert.True(t, strings.HasPrefix(output[0], "bestmove "))
+
+	assert.False(t, session.Execute("quit"))
+}
+
+func TestSetOption(t *testing.T) {
+	tests := map[string]struct {
+		command string
+		name    string
+		value   string
+	}{
+		"hash":              {command: "setoption name Hash value 64", name: "Hash", value: "64"},
+		"names with spaces": {command: "setoption name Clear Hash", name: "Clear Hash", value: ""},
+		"values with space": {command: "setoption name Book File value my book.bin", name: "Book File", value: "my book.bin"},
+	}
+	for tName, test := range tests {
+		name, value := parseSetOption(strings.Fields(test.command))
+		assert.Equal(t, test.name, name, tName)
+		assert.Equal(t, test.value, value, tName)
+	}
+
+	session := NewSession(func(string) {})
+	defaultTable := session.tt
+	session.Execute("setoption name Hash value 0")
+	assert.True(t, defaultTable == session.tt)
+	session.Execute("setoption name Hash value 1")
+	assert.False(t, defaultTable == session.tt)
+
+	session.Execute("setoption name NullMove value false")
+	session.Execute("setoption name lmr value false")
+	session.Execute("setoption name PVS value maybe")
+	assert.False(t, session.options.NullMove)
+	assert.False(t, session.options.LMR)
+	assert.True(t, session.options.CheckExtensions)
+	assert.True(t, session.options.PVS)
+}
+
+func TestParseGo(t *testing.T) {
+	params := parseGo(strings.Fields("go wtime 60000 btime 30000 winc 1000 binc 500 movestogo 20"))
+	assert.Equal(t, 60*time.Second, params.timeControl.WhiteTime)
+	assert.Equal(t, 30*time.Second, params.timeControl.BlackTime)
+	assert.Equal(t, time.Second, params.timeControl.WhiteIncrement)
+	assert.Equal(t, 500*time.Millisecond, params.timeControl.BlackIncrement)
+	assert.Equal(t, 20, params.timeControl.MovesToGo)
+
+	params = parseGo(strings.Fields("go movetime 1500 depth 7"))
+	assert.Equal(t, 1500*time.Millisecond, params.timeControl.MoveTime)
+	assert.Equal(t, 7, params.depth)
+
+	params = parseGo(strings.Fields("go nodes 5000"))
+	assert.Equal(t, 5000, params.nodes)
+	limits := searchLimits(params, position.White)
+	assert.Equal(t, engine.MaxDepth, limits.Depth)
+	assert.Equal(t, 5000, limits.Nodes)
+
+	params = parseGo(strings.Fields("go perft 3"))
+	assert.Equal(t, 3, params.perft)
+}
+
+func TestGoPerft(t *testing.T) {
+	var output []string
+	session := NewSession(func(msg string) {
+		output = append(output, msg)
+	})
+	session.Execute("position startpos moves e2e4")
+	session.Execute("go perft 2")
+	assert.Nil(t, session.searchDone)
+	assert.Equal(t, 22, len(output))
+	assert.Contains(t, output, "d7d5: 31")
+	assert.Equal(t, "", output[20])
+	assert.Equal(t, "Nodes searched: 600", output[21])
+}
+
+// recorder collects the session output, searches write from their own goroutine
+type recorder struct {
+	mu     sync.Mutex
+	output []string
+}
+
+func (r *recorder) write(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.output = append(r.output, msg)
+}
+
+// lines returns everything written so far apart from info lines
+func (r *recorder) lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return withoutInfo(r.output)
+}
+
+func withoutInfo(output []string) []string {
+	var lines []string
+	for _, line := range output {
+		if !strings.HasPrefix(line, "info ") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestInfiniteSearchRunsUntilStopped(t *testing.T) {
+	r := &recorder{}
+	session := NewSession(r.write)
+	session.Execute("position startpos")
+	session.Execute("go infinite")
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, r.lines())
+	// the session keeps reading commands while searching
+	session.Execute("isready")
+	assert.Equal(t, []string{"readyok"}, r.lines())
+	session.Execute("stop")
+	lines := r.lines()
+	assert.Equal(t, 2, len(lines))
+	assert.True(t, strings.HasPrefix(lines[1], "bestmove "))
+}
+
+func TestPonderHitSwitchesToTimedSearch(t *testing.T) {
+	r := &recorder{}
+	session := NewSession(r.write)
+	session.Execute("position startpos moves e2e4")
+	session.Execute("go ponder wtime 1000 btime 1000")
+	time.Sleep(100 * time.Millisecond)
+	// pondering never reports a move on its own
+	assert.Empty(t, r.lines())
+	session.Execute("ponderhit")
+	<-session.searchDone
+	lines := r.lines()
+	assert.Equal(t, 1, len(lines))
+	assert.True(t, strings.HasPrefix(lines[0], "bestmove "))
+}
+
+func TestFormatInfo(t *testing.T) {
+	info := engine.SearchInfo{
+		Depth:    3,
+		SelDepth: 5,
+		Score:    -42,
+		Nodes:    5000,
+		Time:     500 * time.Millisecond,
+		PV:       []moves.Move{*moves.NewMove([]int{52, 36}), *moves.NewMove([]int{12, 28})},
+		HashFull: 12,
+	}
+	assert.Equal(t, "info depth 3 seldepth 5 score cp -42 nodes 5000 nps 10000 hashfull 12 time 500 pv e2e4 e7e5", formatInfo(info))
+
+	info.Score = engine.MateScore - 3
+	assert.Equal(t, "info depth 3 seldepth 5 score mate 2 nodes 5000 nps 10000 hashfull 12 time 500 pv e2e4 e7e5", formatInfo(info))
+	info.Score = -engine.MateScore + 2
+	assert.Equal(t, "info depth 3 seldepth 5 score mate -1 nodes 5000 nps 10000 hashfull 12 time 500 pv e2e4 e7e5", formatInfo(info))
+
+	info.Score, info.LowerBound = 75, true
+	assert.Equal(t, "info depth 3 seldepth 5 score cp 75 lowerbound nodes 5000 nps 10000 hashfull 12 time 500 pv e2e4 e7e5", formatInfo(info))
+	info.LowerBound, info.UpperBound = false, true
+	assert.Equal(t, "info depth 3 seldepth 5 score cp 75 upperbound nodes 5000 nps 10000 hashfull 12 time 500 pv e2e4 e7e5", formatInfo(info))
+
+	info.PV = nil
+	assert.Equal(t, "info depth 3 seldepth 5 score cp 75 upperbound nodes 5000 nps 10000 hashfull 12 time 500", formatInfo(info))
+}
+
+func TestSearchReportsInfo(t *testing.T) {
+	r := &recorder{}
+	session := NewSession(r.write)
+	session.Execute("go depth 3")
+	<-session.searchDone
+	lines := r.output
+	assert.Equal(t, 4, len(lines))
+	for depth, line := range lines[:3] {
+		assert.True(t, strings.HasPrefix(line, fmt.Sprintf("info depth %d ", depth+1)), line)
+	}
+	assert.True(t, strings.HasPrefix(lines[3], "bestmove "))
+}
+
+func TestFormatBestMove(t *testing.T) {
+	e2e4 := moves.NewMove([]int{52, 36})
+	e7e5 := moves.NewMove([]int{12, 28})
+	assert.Equal(t, "bestmove e2e4 ponder e7e5", formatBestMove(*e2e4, []moves.Move{*e2e4, *e7e5}))
+	assert.Equal(t, "bestmove e2e4", formatBestMove(*e2e4, []moves.Move{*e2e4}))
+	assert.Equal(t, "bestmove 0000", formatBestMove(moves.Move{}, nil))
+}
+
+func TestSearchWithoutLegalMoves(t *testing.T) {
+	tests := map[string]struct {
+		pos   string
+		score string
+	}{
+		"mated":      {pos: "position fen rnb1kbnr/pppp1ppp/8/4p3/6Pq/5P2/PPPPP2P/RNBQKBNR w KQkq - 1 3", score: "score mate 0 "},
+		"stalemated": {pos: "position fen 7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", score: "score cp 0 "},
+	}
+	for tName, test := range tests {
+		r := &recorder{}
+		session := NewSession(r.write)
+		session.Execute(test.pos)
+		session.Execute("go depth 3")
+		<-session.searchDone
+		assert.Equal(t, "bestmove 0000", r.output[len(r.output)-1], tName)
+		for _, line := range r.output[:len(r.output)-1] {
+			assert.Contains(t, line, test.score, tName)
+			assert.NotContains(t, line, " pv", tName)
+		}
+	}
+}
+
+func TestThreefoldRepetition(t *testing.T) {
+	r := &recorder{}
+	session := NewSession(r.write)
+	session.Execute("position startpos moves g1f3 g8f6 f3g1 f6g8 g1f3 g8f6 f3g1")
+	session.Execute("go depth 1")
+	<-session.searchDone
+	assert.NotContains(t, r.output, "info string draw by threefold repetition")
+
+	r = &recorder{}
+	session = NewSession(r.write)
+	session.Execute("position startpos moves g1f3 g8f6 f3g1 f6g8 g1f3 g8f6 f3g1 f6g8")
+	session.Execute("go depth 1")
+	// the drawn game is not searched
+	assert.Nil(t, session.searchDone)
+	assert.Equal(t, []string{"info string draw by threefold repetition", "bestmove 0000"}, r.output)
+}
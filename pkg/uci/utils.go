@@ -0,0 +1,254 @@
+package uci
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tonyOreglia/glee/pkg/engine"
+	"github.com/tonyOreglia/glee/pkg/generate"
+	"github.com/tonyOreglia/glee/pkg/moves"
+	"github.com/tonyOreglia/glee/pkg/position"
+)
+
+// handleMove plays a move given in long algebraic notation, e.g. e2e4 or e7e8q.
+// The position is left untouched when the move is not legal.
+func handleMove(mv string, p **position.Position) bool {
+	lookupPromo := map[string]int{
+		// Queen = 2 Bishops = 3 Knights = 4 Rooks = 5
+		"Q": 2,
+		"B": 3,
+		"N": 4,
+		"R": 5,
+		"q": 2,
+		"b": 3,
+		"n": 4,
+		"r": 5,
+	}
+	promotionPiece := 0
+	if len(mv) != 4 && len(mv) != 5 {
+		badInput(mv)
+		return false
+	}
+	if len(mv) == 5 {
+		promotionPiece = lookupPromo[string(mv[4])]
+	}
+	origin, err := moves.ConvertAlgebriacToIndex(mv[0:2])
+	if err != nil {
+		badInput(mv)
+		return false
+	}
+	dest, err := moves.ConvertAlgebriacToIndex(mv[2:4])
+	if err != nil {
+		badInput(mv)
+		return false
+	}
+	move, found := generate.GenerateMoves(*p).FindMove(origin, dest, promotionPiece)
+	if !found {
+		badInput(mv)
+		return false
+	}
+	if !engine.MakeValidMove(move, p) {
+		badInput(mv)
+		return false
+	}
+	return true
+}
+
+// setPosition handles `position [fen <fenstring> | startpos] moves <move1> ... <movei>`.
+// The fen keyword may be omitted, which is how glee's web frontend sends positions.
+func setPosition(p *position.Position, posCommandTokens []string) *position.Position {
+	log.Infof("Setting position: %s", strings.Join(posCommandTokens, " "))
+	UCIPositionTokens := posCommandTokens[1:]
+	if len(UCIPositionTokens) == 0 {
+		badInput(strings.Join(posCommandTokens, " "))
+		return p
+	}
+
+	switch UCIPositionTokens[0] {
+	case "startpos":
+		p = position.StartingPosition()
+		UCIPositionTokens = UCIPositionTokens[1:]
+	case "fen":
+		UCIPositionTokens = UCIPositionTokens[1:]
+		fallthrough
+	default:
+		if len(UCIPositionTokens) < 6 {
+			badInput(strings.Join(posCommandTokens, " "))
+			return p
+		}
+		fenString := strings.Join(UCIPositionTokens[0:6], " ")
+		newPos, err := position.NewPositionFen(fenString)
+		if err != nil {
+			badInput(fenString)
+			return p
+		}
+		p = newPos
+		UCIPositionTokens = UCIPositionTokens[6:]
+	}
+
+	if len(UCIPositionTokens) > 0 && UCIPositionTokens[0] == "moves" {
+		UCIPositionTokens = UCIPositionTokens[1:]
+	}
+	for _, posToken := range UCIPositionTokens {
+		log.Infof("handling position token %s", posToken)
+		if !handleMove(posToken, &p) {
+			break
+		}
+	}
+	return p
+}
+
+// default search depth when go is sent without any limits
+const defaultSearchDepth = 5
+
+// goParams holds the search limits sent with the go command
+type goParams struct {
+	timeControl engine.TimeControl
+	depth       int
+	nodes       int
+	// perft counts leaf nodes to this depth instead of searching
+	perft    int
+	infinite bool
+	ponder   bool
+}
+
+// parseGo reads `go [ponder] [infinite] [wtime x] [btime x] [winc x] [binc x] [movestogo x] [movetime x] [depth x] [nodes x] [perft x]`
+func parseGo(goCommandTokens []string) goParams {
+	params := goParams{}
+	tokens := goCommandTokens[1:]
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "infinite":
+			params.infinite = true
+		case "ponder":
+			params.ponder = true
+		case "wtime", "btime", "winc", "binc", "movestogo", "movetime", "depth", "nodes", "perft":
+			if i+1 == len(tokens) {
+				badInput(strings.Join(goCommandTokens, " "))
+				return params
+			}
+			value, err := strconv.Atoi(tokens[i+1])
+			if err != nil {
+				badInput(strings.Join(goCommandTokens, " "))
+				return params
+			}
+			setGoParam(&params, tokens[i], value)
+			i++
+		default:
+			log.Warnf("go %s not yet implemented", tokens[i])
+		}
+	}
+	return params
+}
+
+func setGoParam(params *goParams, name string, value int) {
+	ms := time.Duration(value) * time.Millisecond
+	switch name {
+	case "wtime":
+		params.timeControl.WhiteTime = ms
+	case "btime":
+		params.timeControl.BlackTime = ms
+	case "winc":
+		params.timeControl.WhiteIncrement = ms
+	case "binc":
+		params.timeControl.BlackIncrement = ms
+	case "movestogo":
+		params.timeControl.MovesToGo = value
+	case "movetime":
+		params.timeControl.MoveTime = ms
+	case "depth":
+		params.depth = value
+	case "nodes":
+		params.nodes = value
+	case "perft":
+		params.perft = value
+	}
+}
+
+// maxHashSize is the largest transposition table in megabytes a GUI may ask for
+const maxHashSize = 1024
+
+// parseSetOption splits "setoption name <id> [value <x>]" into id and value,
+// both of which may contain spaces
+func parseSetOption(tokens []string) (string, string) {
+	var name, value []string
+	current := &name
+	for _, token := range tokens[1:] {
+		switch token {
+		case "name":
+			current = &name
+		case "value":
+			current = &value
+		default:
+			*current = append(*current, token)
+		}
+	}
+	return strings.Join(name, " "), strings.Join(value, " ")
+}
+
+// searchLimits picks the time manager and limits of the search for the go parameters
+func searchLimits(params goParams, activeSide int) engine.SearchLimits {
+	budget := params.timeControl.Budget(activeSide)
+	var tm *engine.TimeManager
+	switch {
+	case params.infinite:
+		tm = engine.NewInfiniteTimeManager()
+	case params.ponder:
+		tm = engine.NewPonderTimeManager(budget)
+	default:
+		tm = engine.NewTimeManager(budget)
+	}
+	depth := params.depth
+	if depth == 0 {
+		depth = defaultSearchDepth
+		if params.infinite || budget > 0 || params.nodes > 0 {
+			depth = engine.MaxDepth
+		}
+	}
+	return engine.SearchLimits{Depth: depth, Nodes: params.nodes, Timer: tm}
+}
+
+// formatInfo converts search progress to a UCI info line
+func formatInfo(info engine.SearchInfo) string {
+	pv := make([]string, len(info.PV))
+	for i := range info.PV {
+		pv[i] = info.PV[i].String()
+	}
+	score := fmt.Sprintf("cp %d", info.Score)
+	if mateIn, ok := info.MateIn(); ok {
+		score = fmt.Sprintf("mate %d", mateIn)
+	}
+	if info.LowerBound {
+		score += " lowerbound"
+	} else if info.UpperBound {
+		score += " upperbound"
+	}
+	line := fmt.Sprintf("info depth %d seldepth %d score %s nodes %d nps %d hashfull %d time %d",
+		info.Depth, info.SelDepth, score, info.Nodes, info.NodesPerSecond(), info.HashFull,
+		info.Time.Nanoseconds()/int64(time.Millisecond))
+	// there is no pv without a legal move
+	if len(pv) > 0 {
+		line += " pv " + strings.Join(pv, " ")
+	}
+	return line
+}
+
+// formatBestMove reports the move to play along with the expected reply to ponder on.
+// Without a legal move, when mated or stalemated, the null move 0000 is sent.
+func formatBestMove(move moves.Move, pv []moves.Move) string {
+	if move == (moves.Move{}) {
+		return "bestmove 0000"
+	}
+	if len(pv) > 1 {
+		return fmt.Sprintf("bestmove %s ponder %s", move.String(), pv[1].String())
+	}
+	return fmt.Sprintf("bestmove %s", move.String())
+}
+
+// badInput is logged rather than printed since stdout may be the UCI channel
+func badInput(c string) {
+	log.Warnf("input correct ?: %s", c)
+}